@@ -0,0 +1,15 @@
+package main
+
+import (
+	"log"
+	"os"
+
+	"seat-management-backend/internal/command"
+)
+
+// migrate は `seat-management-backend migrate` と同じ処理を単体で提供する
+func main() {
+	if err := command.Migrate(os.Args[1:]); err != nil {
+		log.Fatal(err)
+	}
+}
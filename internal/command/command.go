@@ -0,0 +1,143 @@
+package command
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"gorm.io/gorm"
+
+	"seat-management-backend/internal/metrics"
+	"seat-management-backend/internal/middleware"
+	"seat-management-backend/pkg/config"
+	"seat-management-backend/pkg/database"
+	"seat-management-backend/pkg/logger"
+	"seat-management-backend/pkg/tracing"
+)
+
+const usage = `Usage: seat-management-backend <command> [flags]
+
+Commands:
+  serve        HTTPサーバーを起動（デフォルト）
+  migrate      データベースマイグレーションを実行
+  worker       バックグラウンドジョブを実行
+  sync-clerk   Clerkのユーザーをusersテーブルに同期
+  seed         開発用のサンプルデータを投入
+`
+
+var commands = map[string]func(args []string) error{
+	"serve":      Serve,
+	"migrate":    Migrate,
+	"worker":     Worker,
+	"sync-clerk": SyncClerk,
+	"seed":       Seed,
+}
+
+// Run はサブコマンドを実行する
+// 引数がない場合は後方互換のためserveとして扱う
+func Run(args []string) error {
+	if len(args) == 0 {
+		return Serve(nil)
+	}
+
+	if args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		fmt.Fprint(os.Stderr, usage)
+		return nil
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprint(os.Stderr, usage)
+		return fmt.Errorf("unknown command: %s", args[0])
+	}
+	return cmd(args[1:])
+}
+
+// newFlagSet はサブコマンド用のFlagSetを作成する
+func newFlagSet(name string) *flag.FlagSet {
+	return flag.NewFlagSet(name, flag.ExitOnError)
+}
+
+// loadConfig は設定を読み込み、全コマンド共通の必須項目を検証する
+func loadConfig() (*config.Config, error) {
+	return loadConfigWith((*config.Config).Validate)
+}
+
+// loadDatabaseConfig は設定を読み込み、データベースの設定だけを検証する
+// migrateのようにDBにしか接続しないコマンドで、使わない設定の不備で失敗しないようにする
+func loadDatabaseConfig() (*config.Config, error) {
+	return loadConfigWith(func(cfg *config.Config) error { return cfg.Database.Validate() })
+}
+
+func loadConfigWith(validate func(*config.Config) error) (*config.Config, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	if err := validate(cfg); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	logger.Setup(cfg.Log)
+	return cfg, nil
+}
+
+// connectDB はデータベースに接続する
+func connectDB(cfg config.DatabaseConfig) (*gorm.DB, error) {
+	db, err := database.NewPostgresDB(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+	return db, nil
+}
+
+// initClerk はClerk SDKを初期化する
+func initClerk(cfg config.ClerkConfig) error {
+	if err := middleware.InitClerk(cfg); err != nil {
+		return fmt.Errorf("failed to initialize Clerk: %w", err)
+	}
+	return nil
+}
+
+// newMetrics はメトリクスが有効な場合にMetricsを作成し、コネクションプールの統計を登録する
+// 無効な場合はnilを返す
+func newMetrics(cfg config.MetricsConfig, db *gorm.DB) *metrics.Metrics {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	m := metrics.New()
+	if sqlDB, err := db.DB(); err == nil {
+		m.RegisterDBStats(sqlDB, "primary")
+	}
+	return m
+}
+
+// setupTracing はトレーシングを初期化する
+// 返り値の関数は終了時に呼び出し、未送信のスパンを送信する
+func setupTracing(cfg config.TracingConfig) (func(), error) {
+	shutdown, err := tracing.Setup(context.Background(), cfg, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize tracing: %w", err)
+	}
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdown(ctx); err != nil {
+			slog.Error("failed to shutdown tracer provider", "error", err)
+		}
+	}, nil
+}
+
+// closeDB はコネクションプールを閉じる
+func closeDB(db *gorm.DB) {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return
+	}
+	if err := sqlDB.Close(); err != nil {
+		slog.Error("failed to close database", "error", err)
+	}
+}
@@ -0,0 +1,33 @@
+package command
+
+import "testing"
+
+func TestLoadDatabaseConfigIgnoresOtherSections(t *testing.T) {
+	t.Setenv("CONFIG_FILE", "")
+	t.Setenv("POSTGRES_USER", "postgres")
+	t.Setenv("POSTGRES_DB", "seat_management")
+	// migrateに関係のない設定の不備
+	t.Setenv("STORAGE_BACKEND", "unknown")
+	t.Setenv("WORKER_SHUTDOWN_TIMEOUT", "0s")
+
+	if _, err := loadConfig(); err == nil {
+		t.Fatal("loadConfig should reject the invalid storage and worker settings")
+	}
+	cfg, err := loadDatabaseConfig()
+	if err != nil {
+		t.Fatalf("loadDatabaseConfig: %v", err)
+	}
+	if cfg.Database.Name != "seat_management" {
+		t.Errorf("database name = %q, want seat_management", cfg.Database.Name)
+	}
+}
+
+func TestLoadDatabaseConfigValidatesDatabase(t *testing.T) {
+	t.Setenv("CONFIG_FILE", "")
+	t.Setenv("POSTGRES_USER", "")
+	t.Setenv("POSTGRES_DB", "")
+
+	if _, err := loadDatabaseConfig(); err == nil {
+		t.Fatal("loadDatabaseConfig should reject a missing database name and user")
+	}
+}
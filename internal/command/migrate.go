@@ -0,0 +1,120 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+
+	"seat-management-backend/pkg/database"
+)
+
+const migrateUsage = `Usage: migrate [-dir path] <command> [args]
+
+Commands:
+  up [n]         未適用のマイグレーションを適用（nを省略するとすべて）
+  down [n]       直近のマイグレーションをロールバック（デフォルト1件）
+  status         マイグレーションの適用状況を表示
+  create <name>  新しいマイグレーションファイルを作成
+`
+
+// Migrate はマイグレーションのサブコマンドを実行する
+func Migrate(args []string) error {
+	fs := newFlagSet("migrate")
+	dir := fs.String("dir", "pkg/database/migrations", "マイグレーションファイルのディレクトリ（createで使用）")
+	fs.Usage = func() { fmt.Fprint(os.Stderr, migrateUsage) }
+	_ = fs.Parse(args)
+
+	args = fs.Args()
+	if len(args) == 0 {
+		fs.Usage()
+		return fmt.Errorf("migrate command is required")
+	}
+
+	// createはDB接続不要
+	if args[0] == "create" {
+		if len(args) < 2 {
+			return fmt.Errorf("migration name is required")
+		}
+		paths, err := database.CreateMigration(*dir, args[1])
+		if err != nil {
+			return fmt.Errorf("failed to create migration: %w", err)
+		}
+		for _, p := range paths {
+			fmt.Println("Created", p)
+		}
+		return nil
+	}
+
+	cfg, err := loadDatabaseConfig()
+	if err != nil {
+		return err
+	}
+
+	db, err := connectDB(cfg.Database)
+	if err != nil {
+		return err
+	}
+	defer closeDB(db)
+
+	migrator, err := database.NewMigrator(db)
+	if err != nil {
+		return fmt.Errorf("failed to load migrations: %w", err)
+	}
+
+	ctx := context.Background()
+	switch args[0] {
+	case "up":
+		steps, err := parseSteps(args, 0)
+		if err != nil {
+			return err
+		}
+		n, err := migrator.Up(ctx, steps)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Applied %d migration(s)\n", n)
+	case "down":
+		steps, err := parseSteps(args, 1)
+		if err != nil {
+			return err
+		}
+		if steps == 0 {
+			fs.Usage()
+			return fmt.Errorf("invalid step count for down: must be at least 1")
+		}
+		n, err := migrator.Down(ctx, steps)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Reverted %d migration(s)\n", n)
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, s := range statuses {
+			applied := "pending"
+			if s.AppliedAt != nil {
+				applied = s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d  %-40s  %s\n", s.Version, s.Name, applied)
+		}
+	default:
+		fs.Usage()
+		return fmt.Errorf("unknown migrate command: %s", args[0])
+	}
+	return nil
+}
+
+// parseSteps は2番目の引数から件数を取得する
+func parseSteps(args []string, def int) (int, error) {
+	if len(args) < 2 {
+		return def, nil
+	}
+	n, err := strconv.Atoi(args[1])
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid step count: %s", args[1])
+	}
+	return n, nil
+}
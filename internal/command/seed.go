@@ -0,0 +1,62 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"seat-management-backend/internal/domain/entity"
+	"seat-management-backend/internal/infrastructure/persistence"
+	"seat-management-backend/internal/usecase"
+	"seat-management-backend/pkg/audit"
+)
+
+// 開発用のサンプルユーザー（Clerk IDはダミー）
+var seedUsers = []entity.User{
+	{ClerkUserID: "user_seed_admin", Email: "admin@example.com", Name: "Seed Admin", PrimaryAuthProvider: entity.AuthProviderEmail, DefaultPrivacySetting: entity.PrivacyPublic},
+	{ClerkUserID: "user_seed_alice", Email: "alice@example.com", Name: "Alice Example", PrimaryAuthProvider: entity.AuthProviderGoogle, DefaultPrivacySetting: entity.PrivacyFriends},
+	{ClerkUserID: "user_seed_bob", Email: "bob@example.com", Name: "Bob Example", PrimaryAuthProvider: entity.AuthProviderGitHub, DefaultPrivacySetting: entity.PrivacyPrivate},
+}
+
+// Seed は開発用のサンプルデータを投入する
+// 既に存在するデータはスキップするため、何度実行してもよい
+func Seed(args []string) error {
+	fs := newFlagSet("seed")
+	_ = fs.Parse(args)
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	if cfg.Server.GinMode == "release" {
+		return errors.New("seed is disabled in release mode")
+	}
+
+	db, err := connectDB(cfg.Database)
+	if err != nil {
+		return err
+	}
+
+	auditUsecase := usecase.NewAuditUsecase(persistence.NewAuditEventRepository(db))
+	userUsecase := usecase.NewUserUsecase(persistence.NewUserRepository(db), auditUsecase, nil)
+
+	ctx := audit.WithActor(context.Background(), audit.Actor{Type: audit.ActorSystem, ID: "seed"})
+	created := 0
+	for _, u := range seedUsers {
+		if _, err := userUsecase.GetByClerkUserID(ctx, u.ClerkUserID); err == nil {
+			continue
+		} else if !errors.Is(err, entity.ErrUserNotFound) {
+			return err
+		}
+
+		user := u
+		if err := userUsecase.Create(ctx, &user); err != nil {
+			return fmt.Errorf("failed to seed user %s: %w", u.Email, err)
+		}
+		created++
+	}
+
+	fmt.Printf("Seeded %d user(s)\n", created)
+	return nil
+}
@@ -0,0 +1,191 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+
+	"seat-management-backend/internal/infrastructure/persistence"
+	"seat-management-backend/internal/interface/handler"
+	"seat-management-backend/internal/interface/validation"
+	"seat-management-backend/internal/middleware"
+	"seat-management-backend/internal/usecase"
+	"seat-management-backend/pkg/database"
+	"seat-management-backend/pkg/storage"
+	"seat-management-backend/pkg/tracing"
+)
+
+// Serve はHTTPサーバーを起動する
+func Serve(args []string) error {
+	fs := newFlagSet("serve")
+	migrate := fs.Bool("migrate", false, "起動前に未適用のマイグレーションを適用する（開発用）")
+	_ = fs.Parse(args)
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	if err := cfg.Clerk.Validate(true); err != nil {
+		return fmt.Errorf("invalid config: %w", err)
+	}
+	slog.Info("config loaded", "config", cfg.String())
+	if cfg.Clerk.AdminOrganizationID == "" {
+		slog.Warn("CLERK_ADMIN_ORGANIZATION_ID is not set; all admin API requests will be rejected")
+	}
+
+	gin.SetMode(cfg.Server.GinMode)
+	if err := validation.Register(); err != nil {
+		return err
+	}
+
+	if err := initClerk(cfg.Clerk); err != nil {
+		return err
+	}
+
+	shutdownTracing, err := setupTracing(cfg.Tracing)
+	if err != nil {
+		return err
+	}
+	defer shutdownTracing()
+
+	db, err := connectDB(cfg.Database)
+	if err != nil {
+		return err
+	}
+
+	migrator, err := database.NewMigrator(db)
+	if err != nil {
+		return fmt.Errorf("failed to load migrations: %w", err)
+	}
+
+	// 本番ではmigrateコマンドを別ジョブとして実行する
+	if *migrate {
+		if _, err := migrator.Up(context.Background(), 0); err != nil {
+			return fmt.Errorf("failed to migrate database: %w", err)
+		}
+	}
+
+	m := newMetrics(cfg.Metrics, db)
+
+	// 依存関係の注入
+	userRepo := persistence.NewUserRepository(db)
+	auditRepo := persistence.NewAuditEventRepository(db)
+	auditUsecase := usecase.NewAuditUsecase(auditRepo)
+	userUsecase := usecase.NewUserUsecase(userRepo, auditUsecase, m)
+
+	store, err := storage.New(context.Background(), cfg.Storage)
+	if err != nil {
+		return fmt.Errorf("failed to initialize storage: %w", err)
+	}
+	avatarUsecase := usecase.NewAvatarUsecase(userRepo, auditUsecase, store)
+	dataExportUsecase := usecase.NewDataExportUsecase(userRepo, persistence.NewDataExportRepository(db), auditRepo, store, cfg.Export.SyncMaxRecords, cfg.Export.Retention)
+
+	// ハンドラーの初期化
+	userHandler := handler.NewUserHandler(userUsecase, avatarUsecase, cfg.Storage.MaxUploadBytes)
+	webhookHandler, err := handler.NewWebhookHandler(userUsecase, auditUsecase, avatarUsecase, cfg.Clerk.WebhookSecret.Value(), m)
+	if err != nil {
+		return err
+	}
+	dataExportHandler := handler.NewDataExportHandler(userUsecase, dataExportUsecase)
+	adminUserHandler := handler.NewAdminUserHandler(userUsecase, cfg.Clerk.AdminOrganizationID)
+	adminAuditHandler := handler.NewAdminAuditHandler(auditUsecase, userUsecase, cfg.Clerk.AdminOrganizationID)
+	healthHandler := handler.NewHealthHandler(db, migrator)
+
+	// Ginルーターの初期化
+	r := gin.New()
+	// 監査ログに残すクライアントのIPを詐称されないよう、信頼するプロキシを明示する
+	if err := r.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		return fmt.Errorf("invalid SERVER_TRUSTED_PROXIES: %w", err)
+	}
+	r.Use(middleware.RequestID(), tracing.Middleware(), middleware.RequestLogger())
+	// ErrorHandlerやRecoveryが書き込んだステータスを数えるよう、それらより外側に置く
+	if m != nil {
+		r.Use(m.Middleware())
+	}
+	r.Use(middleware.Recovery(), middleware.Locale(), middleware.ErrorHandler())
+	if m != nil {
+		r.GET(cfg.Metrics.Path, gin.WrapH(m.Handler()))
+	}
+
+	// CORS設定
+	r.Use(cors.New(cors.Config{
+		AllowOrigins:     []string{cfg.Server.FrontendURL},
+		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "If-Match"},
+		ExposeHeaders:    []string{"Content-Length", "ETag"},
+		AllowCredentials: true,
+	}))
+
+	// ローカル保存の場合はアバター画像をこのサーバーで配信する
+	// エクスポートしたアーカイブなど他のオブジェクトは認証付きのAPIからのみ取得できる
+	if local, ok := store.(*storage.LocalStore); ok {
+		r.Static(storage.LocalRoutePrefix+"/"+usecase.AvatarKeyPrefix, filepath.Join(local.Dir(), usecase.AvatarKeyPrefix))
+	}
+
+	// ルートの登録
+	healthHandler.RegisterRoutes(r)
+	userHandler.RegisterRoutes(r)
+	dataExportHandler.RegisterRoutes(r)
+	webhookHandler.RegisterRoutes(r)
+	adminUserHandler.RegisterRoutes(r)
+	adminAuditHandler.RegisterRoutes(r)
+
+	srv := &http.Server{
+		Addr:              ":" + cfg.Server.Port,
+		Handler:           r,
+		ReadTimeout:       cfg.Server.ReadTimeout,
+		ReadHeaderTimeout: cfg.Server.ReadHeaderTimeout,
+		WriteTimeout:      cfg.Server.WriteTimeout,
+		IdleTimeout:       cfg.Server.IdleTimeout,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// サーバー起動
+	errCh := make(chan error, 1)
+	go func() {
+		slog.Info("server starting", "port", cfg.Server.Port)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errCh <- err
+		}
+		close(errCh)
+	}()
+
+	select {
+	case err := <-errCh:
+		closeDB(db)
+		return fmt.Errorf("failed to start server: %w", err)
+	case <-ctx.Done():
+	}
+	stop()
+
+	slog.Info("shutdown signal received")
+	healthHandler.MarkShuttingDown()
+	if cfg.Server.ShutdownDelay > 0 {
+		time.Sleep(cfg.Server.ShutdownDelay)
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer cancel()
+
+	// 新規接続の受付を止め、処理中のリクエストの完了を待つ
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		slog.Warn("graceful shutdown timed out, closing remaining connections", "error", err)
+		_ = srv.Close()
+	}
+
+	closeDB(db)
+	slog.Info("server stopped")
+	return nil
+}
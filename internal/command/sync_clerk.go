@@ -0,0 +1,55 @@
+package command
+
+import (
+	"context"
+	"fmt"
+
+	"seat-management-backend/internal/infrastructure/clerk"
+	"seat-management-backend/internal/infrastructure/persistence"
+	"seat-management-backend/internal/usecase"
+	"seat-management-backend/pkg/audit"
+	"seat-management-backend/pkg/storage"
+)
+
+// SyncClerk はClerkの全ユーザーをusersテーブルに一度だけ同期する
+func SyncClerk(args []string) error {
+	fs := newFlagSet("sync-clerk")
+	_ = fs.Parse(args)
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	if err := initClerk(cfg.Clerk); err != nil {
+		return err
+	}
+
+	db, err := connectDB(cfg.Database)
+	if err != nil {
+		return err
+	}
+
+	// アップロードされたアバター画像をClerkの画像で上書きしないよう、保存先を見分けるために使う
+	store, err := storage.New(context.Background(), cfg.Storage)
+	if err != nil {
+		return fmt.Errorf("failed to initialize storage: %w", err)
+	}
+
+	userRepo := persistence.NewUserRepository(db)
+	auditUsecase := usecase.NewAuditUsecase(persistence.NewAuditEventRepository(db))
+	userSyncUsecase := usecase.NewUserSyncUsecase(clerk.NewUserDirectory(), userRepo, auditUsecase, store, nil)
+
+	ctx := audit.WithActor(context.Background(), audit.Actor{Type: audit.ActorSystem, ID: "sync-clerk"})
+	result, err := userSyncUsecase.SyncAll(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to sync users from Clerk: %w", err)
+	}
+
+	fmt.Printf("Created: %d, Updated: %d, Skipped: %d, Failed: %d\n",
+		result.Created, result.Updated, result.Skipped, result.Failed)
+	if result.Failed > 0 {
+		return fmt.Errorf("%d user(s) failed to sync", result.Failed)
+	}
+	return nil
+}
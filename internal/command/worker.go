@@ -0,0 +1,165 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"seat-management-backend/internal/infrastructure/clerk"
+	"seat-management-backend/internal/infrastructure/persistence"
+	"seat-management-backend/internal/usecase"
+	"seat-management-backend/pkg/storage"
+	"seat-management-backend/pkg/worker"
+)
+
+// Worker はバックグラウンドジョブをシグナルを受け取るまで実行する
+func Worker(args []string) error {
+	fs := newFlagSet("worker")
+	_ = fs.Parse(args)
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	if err := initClerk(cfg.Clerk); err != nil {
+		return err
+	}
+
+	shutdownTracing, err := setupTracing(cfg.Tracing)
+	if err != nil {
+		return err
+	}
+	defer shutdownTracing()
+
+	db, err := connectDB(cfg.Database)
+	if err != nil {
+		return err
+	}
+
+	userRepo := persistence.NewUserRepository(db)
+	m := newMetrics(cfg.Metrics, db)
+	auditRepo := persistence.NewAuditEventRepository(db)
+	auditUsecase := usecase.NewAuditUsecase(auditRepo)
+
+	store, err := storage.New(context.Background(), cfg.Storage)
+	if err != nil {
+		return fmt.Errorf("failed to initialize storage: %w", err)
+	}
+	userSyncUsecase := usecase.NewUserSyncUsecase(clerk.NewUserDirectory(), userRepo, auditUsecase, store, m)
+	dataExportRepo := persistence.NewDataExportRepository(db)
+	dataExportUsecase := usecase.NewDataExportUsecase(userRepo, dataExportRepo, auditRepo, store, cfg.Export.SyncMaxRecords, cfg.Export.Retention)
+	userRetentionUsecase := usecase.NewUserRetentionUsecase(userRepo, dataExportRepo, auditUsecase, store, m, cfg.Retention.DeletedUsers)
+
+	runner := worker.NewRunner()
+	runner.Register(worker.Job{
+		Name:     "clerk-user-sync",
+		Interval: cfg.Worker.ClerkSyncInterval,
+		Run: func(ctx context.Context) error {
+			result, err := userSyncUsecase.SyncAll(ctx)
+			if err != nil {
+				return err
+			}
+			slog.InfoContext(ctx, "worker: clerk sync finished",
+				"created", result.Created, "updated", result.Updated,
+				"skipped", result.Skipped, "failed", result.Failed)
+			return nil
+		},
+	})
+
+	runner.Register(worker.Job{
+		Name:     "user-anonymize",
+		Interval: cfg.Worker.UserAnonymizeInterval,
+		Run: func(ctx context.Context) error {
+			result, err := userRetentionUsecase.AnonymizeDeleted(ctx)
+			if err != nil {
+				return err
+			}
+			if result.Anonymized > 0 || result.Failed > 0 {
+				slog.InfoContext(ctx, "worker: user anonymization finished",
+					"anonymized", result.Anonymized, "failed", result.Failed)
+			}
+			return nil
+		},
+	})
+	runner.Register(worker.Job{
+		Name:     "data-export",
+		Interval: cfg.Worker.DataExportInterval,
+		Run: func(ctx context.Context) error {
+			// 処理待ちがなくなるまで続けて処理する
+			processed := 0
+			for ctx.Err() == nil {
+				ok, err := dataExportUsecase.ProcessNext(ctx)
+				if err != nil {
+					slog.ErrorContext(ctx, "worker: data export failed", "error", err)
+				}
+				if !ok {
+					break
+				}
+				processed++
+			}
+
+			purged, err := dataExportUsecase.PurgeExpired(ctx)
+			if err != nil {
+				return err
+			}
+			if processed > 0 || purged > 0 {
+				slog.InfoContext(ctx, "worker: data exports finished", "processed", processed, "purged", purged)
+			}
+			return nil
+		},
+	})
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// メトリクス専用のHTTPサーバー
+	var metricsServer *http.Server
+	if m != nil {
+		mux := http.NewServeMux()
+		mux.Handle(cfg.Metrics.Path, m.Handler())
+		metricsServer = &http.Server{
+			Addr:              ":" + cfg.Metrics.WorkerPort,
+			Handler:           mux,
+			ReadHeaderTimeout: 5 * time.Second,
+		}
+		go func() {
+			if err := metricsServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				slog.Error("metrics server failed", "error", err)
+			}
+		}()
+	}
+
+	slog.Info("worker started")
+	done := make(chan struct{})
+	go func() {
+		runner.Run(ctx)
+		close(done)
+	}()
+
+	<-ctx.Done()
+	slog.Info("shutdown signal received, waiting for running jobs")
+
+	// 実行中のジョブにはキャンセル済みのctxが渡っているため、通常はすぐに終わる
+	select {
+	case <-done:
+	case <-time.After(cfg.Worker.ShutdownTimeout):
+		slog.Warn("timed out waiting for jobs to finish")
+	}
+
+	if metricsServer != nil {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		_ = metricsServer.Shutdown(shutdownCtx)
+		cancel()
+	}
+
+	closeDB(db)
+	slog.Info("worker stopped")
+	return nil
+}
@@ -0,0 +1,66 @@
+package entity
+
+import (
+	"time"
+
+	"seat-management-backend/pkg/audit"
+	ulidpkg "seat-management-backend/pkg/ulid"
+
+	"gorm.io/gorm"
+)
+
+// AuditAction は監査ログに記録する操作の種類
+type AuditAction string
+
+const (
+	AuditUserCreated     AuditAction = "user.created"
+	AuditUserUpdated     AuditAction = "user.updated"
+	AuditUserDeleted     AuditAction = "user.deleted"
+	AuditUserSuspended   AuditAction = "user.suspended"
+	AuditUserUnsuspended AuditAction = "user.unsuspended"
+	AuditUserRestored    AuditAction = "user.restored"
+	AuditUserAnonymized  AuditAction = "user.anonymized"
+	// Clerkの組織のロールの付与・変更・削除
+	AuditRoleChanged AuditAction = "organization.role_changed"
+
+	// 座席・予約関連（今後追加）
+	// AuditSeatUpdated        AuditAction = "seat.updated"
+	// AuditReservationCreated AuditAction = "reservation.created"
+)
+
+// 監査ログの対象の種類
+const (
+	AuditTargetUser = "user"
+)
+
+// AuditEvent は誰がいつ何をしたかの記録（追記のみで更新・削除はしない）
+type AuditEvent struct {
+	ID         string          `gorm:"type:varchar(26);primary_key" json:"id"`
+	OccurredAt time.Time       `gorm:"type:timestamp with time zone;not null" json:"occurred_at"`
+	ActorType  audit.ActorType `gorm:"type:varchar(20);not null" json:"actor_type"`
+	ActorID    *string         `gorm:"type:varchar(255)" json:"actor_id,omitempty"`
+	ActorRole  *string         `gorm:"type:varchar(100)" json:"actor_role,omitempty"`
+	Action     AuditAction     `gorm:"type:varchar(100);not null" json:"action"`
+	TargetType string          `gorm:"type:varchar(50);not null" json:"target_type"`
+	TargetID   *string         `gorm:"type:varchar(255)" json:"target_id,omitempty"`
+	IPAddress  *string         `gorm:"type:varchar(45)" json:"ip_address,omitempty"`
+	SessionID  *string         `gorm:"type:varchar(255)" json:"session_id,omitempty"`
+	RequestID  *string         `gorm:"type:varchar(64)" json:"request_id,omitempty"`
+	// 変更された項目の変更前後の値
+	Changes audit.Changes `gorm:"type:jsonb;serializer:json" json:"changes,omitempty"`
+}
+
+func (AuditEvent) TableName() string {
+	return "audit_events"
+}
+
+// BeforeCreate はレコード作成前に実行される
+func (e *AuditEvent) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == "" {
+		e.ID = ulidpkg.Generate()
+	}
+	if e.OccurredAt.IsZero() {
+		e.OccurredAt = time.Now()
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package entity
+
+import (
+	"time"
+
+	ulidpkg "seat-management-backend/pkg/ulid"
+
+	"gorm.io/gorm"
+)
+
+type DataExportStatus string
+
+const (
+	DataExportPending    DataExportStatus = "pending"
+	DataExportProcessing DataExportStatus = "processing"
+	DataExportCompleted  DataExportStatus = "completed"
+	DataExportFailed     DataExportStatus = "failed"
+	// 保存期間を過ぎてアーカイブを削除した
+	DataExportExpired DataExportStatus = "expired"
+)
+
+// IsDone は処理が終わっている（これ以上状態が進まない）かどうかを返す
+func (s DataExportStatus) IsDone() bool {
+	return s != DataExportPending && s != DataExportProcessing
+}
+
+// DataExport はユーザーの個人データのエクスポート（非同期ジョブ）
+type DataExport struct {
+	ID     string           `gorm:"type:varchar(26);primary_key" json:"id"`
+	UserID string           `gorm:"type:varchar(26);not null;index" json:"user_id"`
+	Status DataExportStatus `gorm:"type:data_export_status_enum;not null;default:'pending'" json:"status"`
+	// 作成したアーカイブのBlobStore上のキー（利用者には公開しない）
+	ObjectKey *string `gorm:"type:varchar(255)" json:"-"`
+	SizeBytes *int64  `json:"size_bytes,omitempty"`
+	// 失敗した理由（ログ調査用。利用者には公開しない）
+	Error       *string    `gorm:"type:text" json:"-"`
+	StartedAt   *time.Time `gorm:"type:timestamp with time zone" json:"started_at,omitempty"`
+	CompletedAt *time.Time `gorm:"type:timestamp with time zone" json:"completed_at,omitempty"`
+	// この時刻を過ぎるとアーカイブは削除される
+	ExpiresAt *time.Time `gorm:"type:timestamp with time zone" json:"expires_at,omitempty"`
+	CreatedAt time.Time  `gorm:"type:timestamp with time zone;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt time.Time  `gorm:"type:timestamp with time zone;default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+func (DataExport) TableName() string {
+	return "data_exports"
+}
+
+// BeforeCreate はレコード作成前に実行される
+func (e *DataExport) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == "" {
+		e.ID = ulidpkg.Generate()
+	}
+	return nil
+}
@@ -22,14 +22,26 @@ type AuthProvider string
 const (
 	AuthProviderEmail   AuthProvider = "email"
 	AuthProviderGoogle  AuthProvider = "google"
+	AuthProviderGitHub  AuthProvider = "github"
 	AuthProviderUnknown AuthProvider = "unknown"
 )
 
 // IsValid はAuthProviderが有効かチェック
 func (a AuthProvider) IsValid() bool {
 	switch a {
-	case AuthProviderEmail, AuthProviderGoogle, AuthProviderUnknown:
+	case AuthProviderEmail, AuthProviderGoogle, AuthProviderGitHub, AuthProviderUnknown:
 		return true
 	}
 	return false
 }
+
+// AuthProviderFromOAuth はClerkのOAuthプロバイダー名をAuthProviderに変換
+func AuthProviderFromOAuth(provider string) AuthProvider {
+	switch provider {
+	case "oauth_google":
+		return AuthProviderGoogle
+	case "oauth_github":
+		return AuthProviderGitHub
+	}
+	return AuthProviderUnknown
+}
@@ -1,18 +1,92 @@
 package entity
 
-import "errors"
+import "seat-management-backend/pkg/i18n"
+
+// ErrorCode はAPIクライアントに返す安定したエラーコード
+// メッセージは変わり得るため、クライアントはコードで判定すること
+type ErrorCode string
+
+const (
+	CodeUserNotFound          ErrorCode = "USER_NOT_FOUND"
+	CodeInvalidEmail          ErrorCode = "INVALID_EMAIL"
+	CodeInvalidName           ErrorCode = "INVALID_NAME"
+	CodeInvalidLocale         ErrorCode = "INVALID_LOCALE"
+	CodeInvalidPrivacySetting ErrorCode = "INVALID_PRIVACY_SETTING"
+	CodeDuplicateEmail        ErrorCode = "DUPLICATE_EMAIL"
+	CodeDuplicateClerkID      ErrorCode = "DUPLICATE_CLERK_ID"
+	CodeUserSuspended         ErrorCode = "USER_SUSPENDED"
+	CodeUserNotDeleted        ErrorCode = "USER_NOT_DELETED"
+	CodeUserAnonymized        ErrorCode = "USER_ANONYMIZED"
+	CodeInvalidAuthProvider   ErrorCode = "INVALID_AUTH_PROVIDER"
+	CodeInvalidCursor         ErrorCode = "INVALID_CURSOR"
+	CodeVersionConflict       ErrorCode = "VERSION_CONFLICT"
+	CodeUnsupportedImageType  ErrorCode = "UNSUPPORTED_IMAGE_TYPE"
+	CodeInvalidImage          ErrorCode = "INVALID_IMAGE"
+	CodeFileTooLarge          ErrorCode = "FILE_TOO_LARGE"
+	CodeDataExportNotFound    ErrorCode = "DATA_EXPORT_NOT_FOUND"
+	CodeDataExportNotReady    ErrorCode = "DATA_EXPORT_NOT_READY"
+	CodeDataExportExpired     ErrorCode = "DATA_EXPORT_EXPIRED"
+
+	// 座席・予約関連（今後追加）
+	// CodeSeatNotFound       ErrorCode = "SEAT_NOT_FOUND"
+	// CodeReservationConflict ErrorCode = "RESERVATION_CONFLICT"
+)
+
+// Error はコードを持つドメインエラー
+// 各エラーは同一のインスタンスを使い回すため、errors.Isで判定できる
+// メッセージはi18nのカタログでコードをキーに管理する
+type Error struct {
+	Code ErrorCode
+}
+
+// NewError はドメインエラーを作成する
+func NewError(code ErrorCode) *Error {
+	return &Error{Code: code}
+}
+
+// Error はデフォルト言語のメッセージを返す（ログ用）
+func (e *Error) Error() string {
+	return e.Message(i18n.Default)
+}
+
+// Message は指定の言語のメッセージを返す
+func (e *Error) Message(locale i18n.Locale) string {
+	return i18n.T(locale, string(e.Code))
+}
 
 var (
 	// ユーザー関連のエラー
-	ErrUserNotFound     = errors.New("ユーザーが見つかりません")
-	ErrInvalidEmail     = errors.New("無効なメールアドレスです")
-	ErrInvalidName      = errors.New("無効な名前です")
-	ErrDuplicateEmail   = errors.New("このメールアドレスは既に使用されています")
-	ErrDuplicateClerkID = errors.New("このClerk IDは既に使用されています")
+	ErrUserNotFound          = NewError(CodeUserNotFound)
+	ErrInvalidEmail          = NewError(CodeInvalidEmail)
+	ErrInvalidName           = NewError(CodeInvalidName)
+	ErrInvalidLocale         = NewError(CodeInvalidLocale)
+	ErrInvalidPrivacySetting = NewError(CodeInvalidPrivacySetting)
+	ErrDuplicateEmail        = NewError(CodeDuplicateEmail)
+	ErrDuplicateClerkID      = NewError(CodeDuplicateClerkID)
+	ErrUserSuspended         = NewError(CodeUserSuspended)
+	ErrUserNotDeleted        = NewError(CodeUserNotDeleted)
+	ErrUserAnonymized        = NewError(CodeUserAnonymized)
+
+	// 楽観的ロックのエラー（他の更新と競合した、またはIf-Matchが最新でない）
+	ErrVersionConflict = NewError(CodeVersionConflict)
+
+	// 画像アップロードのエラー
+	ErrUnsupportedImageType = NewError(CodeUnsupportedImageType)
+	ErrInvalidImage         = NewError(CodeInvalidImage)
+	ErrFileTooLarge         = NewError(CodeFileTooLarge)
+
+	// 個人データのエクスポートのエラー
+	ErrDataExportNotFound = NewError(CodeDataExportNotFound)
+	ErrDataExportNotReady = NewError(CodeDataExportNotReady)
+	ErrDataExportExpired  = NewError(CodeDataExportExpired)
+
+	// 検索条件のエラー
+	ErrInvalidAuthProvider = NewError(CodeInvalidAuthProvider)
+	ErrInvalidCursor       = NewError(CodeInvalidCursor)
 
 	// 座席関連のエラー（今後追加）
-	// ErrSeatNotFound = errors.New("座席が見つかりません")
+	// ErrSeatNotFound = NewError(CodeSeatNotFound)
 
 	// 予約関連のエラー（今後追加）
-	// ErrReservationNotFound = errors.New("予約が見つかりません")
+	// ErrReservationConflict = NewError(CodeReservationConflict)
 )
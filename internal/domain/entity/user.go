@@ -1,8 +1,11 @@
 package entity
 
 import (
+	"fmt"
+	"strings"
 	"time"
 
+	"seat-management-backend/pkg/audit"
 	ulidpkg "seat-management-backend/pkg/ulid"
 
 	"gorm.io/gorm"
@@ -10,18 +13,34 @@ import (
 
 type User struct {
 	ID                    string         `gorm:"type:varchar(26);primary_key" json:"id"`
-	ClerkUserID           string         `gorm:"type:varchar(255);uniqueIndex:idx_users_clerk_id;not null" json:"clerk_user_id"`
-	Email                 string         `gorm:"type:varchar(255);uniqueIndex:idx_users_email;not null" json:"email"`
+	ClerkUserID           string         `gorm:"type:varchar(255);uniqueIndex:idx_users_clerk_id,where:deleted_at IS NULL;not null" json:"clerk_user_id"`
+	Email                 string         `gorm:"type:varchar(255);uniqueIndex:idx_users_email,where:deleted_at IS NULL;not null" json:"email"`
 	Name                  string         `gorm:"type:varchar(100);not null" json:"name"`
 	AvatarURL             *string        `gorm:"type:varchar(500)" json:"avatar_url,omitempty"`
 	PrimaryAuthProvider   AuthProvider   `gorm:"type:auth_provider_enum;default:'unknown'" json:"primary_auth_provider"`
 	DefaultPrivacySetting PrivacySetting `gorm:"type:privacy_setting_enum;default:'private'" json:"default_privacy_setting"`
+	Locale                *string        `gorm:"type:varchar(10)" json:"locale,omitempty"`
 	LastLoginAt           *time.Time     `gorm:"type:timestamp with time zone" json:"last_login_at,omitempty"`
-	CreatedAt             time.Time      `gorm:"type:timestamp with time zone;default:CURRENT_TIMESTAMP" json:"created_at"`
-	UpdatedAt             time.Time      `gorm:"type:timestamp with time zone;default:CURRENT_TIMESTAMP" json:"updated_at"`
-	DeletedAt             gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+	SuspendedAt           *time.Time     `gorm:"type:timestamp with time zone" json:"suspended_at,omitempty"`
+	// 削除から一定期間が過ぎて個人情報を消去した時刻（消去後は復元できない）
+	AnonymizedAt *time.Time `gorm:"type:timestamp with time zone" json:"anonymized_at,omitempty"`
+	// 楽観的ロック用のバージョン（更新のたびに1増える）
+	Version   int64          `gorm:"not null;default:1" json:"version"`
+	CreatedAt time.Time      `gorm:"type:timestamp with time zone;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt time.Time      `gorm:"type:timestamp with time zone;default:CURRENT_TIMESTAMP" json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
 }
 
+// 部分更新で指定するカラム名
+const (
+	UserColumnEmail                 = "email"
+	UserColumnName                  = "name"
+	UserColumnAvatarURL             = "avatar_url"
+	UserColumnPrimaryAuthProvider   = "primary_auth_provider"
+	UserColumnDefaultPrivacySetting = "default_privacy_setting"
+	UserColumnLocale                = "locale"
+)
+
 func (User) TableName() string {
 	return "users"
 }
@@ -40,3 +59,87 @@ func (u *User) UpdateLastLogin() {
 	now := time.Now()
 	u.LastLoginAt = &now
 }
+
+// IsSuspended はユーザーが利用停止中かどうかを返す
+func (u *User) IsSuspended() bool {
+	return u.SuspendedAt != nil
+}
+
+// AnonymizedUserName は個人情報を消去したユーザーの表示名
+const AnonymizedUserName = "Deleted user"
+
+// IsAnonymized は個人情報を消去済みかどうかを返す
+func (u *User) IsAnonymized() bool {
+	return u.AnonymizedAt != nil
+}
+
+// Anonymize は個人を特定できる項目を消去する
+// 予約などの集計に使うため、行自体は残して墓標（tombstone）として扱う
+// メールアドレスなどはIDから作る一意の値に置き換える
+func (u *User) Anonymize(now time.Time) {
+	u.ClerkUserID = "deleted_" + u.ID
+	u.Email = u.ID + "@deleted.invalid"
+	u.Name = AnonymizedUserName
+	u.AvatarURL = nil
+	u.Locale = nil
+	u.LastLoginAt = nil
+	u.AnonymizedAt = &now
+}
+
+// userAuditRedactedFields は監査ログに値を残さない個人情報の項目
+// 監査ログは追記のみで、ユーザーの個人情報を消去しても消せないため
+var userAuditRedactedFields = []string{UserColumnEmail, UserColumnName, UserColumnAvatarURL}
+
+// UserAuditChanges はbeforeからafterへの変更を監査ログ用に返す（作成時はbeforeにnilを渡す）
+// メールアドレスなどの個人情報は値を残さず、変更されたことだけを記録する
+func UserAuditChanges(before, after *User) audit.Changes {
+	var snapshot map[string]any
+	if before != nil {
+		snapshot = before.AuditSnapshot()
+	}
+	return audit.Diff(snapshot, after.AuditSnapshot()).Redact(userAuditRedactedFields...)
+}
+
+// AuditSnapshot は監査ログで変更前後を比べる項目を返す
+// 個人情報の値も含むため、記録にはUserAuditChangesを使う
+func (u *User) AuditSnapshot() map[string]any {
+	return map[string]any{
+		UserColumnEmail:                 u.Email,
+		UserColumnName:                  u.Name,
+		UserColumnAvatarURL:             derefString(u.AvatarURL),
+		UserColumnPrimaryAuthProvider:   string(u.PrimaryAuthProvider),
+		UserColumnDefaultPrivacySetting: string(u.DefaultPrivacySetting),
+		UserColumnLocale:                derefString(u.Locale),
+		"suspended_at":                  derefTime(u.SuspendedAt),
+	}
+}
+
+func derefString(s *string) any {
+	if s == nil {
+		return nil
+	}
+	return *s
+}
+
+func derefTime(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// BuildDisplayName は姓名から表示名を組み立てる
+// 姓名がどちらもない場合はメールアドレスのローカル部を使う（emailが空なら空文字）
+func BuildDisplayName(firstName, lastName *string, email string) string {
+	switch {
+	case firstName != nil && lastName != nil:
+		return fmt.Sprintf("%s %s", *firstName, *lastName)
+	case firstName != nil:
+		return *firstName
+	case lastName != nil:
+		return *lastName
+	case email != "":
+		return strings.Split(email, "@")[0]
+	}
+	return ""
+}
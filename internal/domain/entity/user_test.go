@@ -0,0 +1,77 @@
+package entity
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUserAuditChangesRedactsPII(t *testing.T) {
+	avatar := "https://cdn.example.com/avatars/alice.jpg"
+	before := &User{
+		ID:                    "01J00000000000000000000001",
+		Email:                 "alice@example.com",
+		Name:                  "Alice Example",
+		AvatarURL:             &avatar,
+		PrimaryAuthProvider:   AuthProviderEmail,
+		DefaultPrivacySetting: PrivacyPublic,
+	}
+	after := *before
+	after.Email = "alice@new.example.com"
+	after.Name = "Alice New"
+	after.AvatarURL = nil
+	after.DefaultPrivacySetting = PrivacyPrivate
+
+	for name, changes := range map[string]any{
+		"update": UserAuditChanges(before, &after),
+		"create": UserAuditChanges(nil, before),
+	} {
+		b, err := json.Marshal(changes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, pii := range []string{"alice@", "Alice", "avatars/alice"} {
+			if strings.Contains(string(b), pii) {
+				t.Errorf("%s: audit changes contain personal data %q: %s", name, pii, b)
+			}
+		}
+	}
+
+	changes := UserAuditChanges(before, &after)
+	for _, field := range []string{UserColumnEmail, UserColumnName, UserColumnAvatarURL} {
+		if c, ok := changes[field]; !ok || !c.Redacted {
+			t.Errorf("%s: change should be recorded as redacted, got %+v (present=%v)", field, c, ok)
+		}
+	}
+	if c := changes[UserColumnDefaultPrivacySetting]; c.Redacted || c.Before != "public" || c.After != "private" {
+		t.Errorf("non-personal fields should keep their values, got %+v", c)
+	}
+}
+
+func TestUserAuditChangesUnchanged(t *testing.T) {
+	u := &User{Email: "bob@example.com", Name: "Bob"}
+	copied := *u
+	if changes := UserAuditChanges(u, &copied); len(changes) != 0 {
+		t.Errorf("UserAuditChanges() = %v, want no changes", changes)
+	}
+}
+
+func TestAnonymize(t *testing.T) {
+	avatar := "https://cdn.example.com/a.jpg"
+	locale := "ja"
+	now := time.Now()
+	u := &User{ID: "01J00000000000000000000001", ClerkUserID: "user_abc", Email: "carol@example.com", Name: "Carol", AvatarURL: &avatar, Locale: &locale, LastLoginAt: &now}
+
+	u.Anonymize(now)
+
+	if !u.IsAnonymized() {
+		t.Error("IsAnonymized() should be true")
+	}
+	if u.ClerkUserID != "deleted_"+u.ID || u.Email != u.ID+"@deleted.invalid" || u.Name != AnonymizedUserName {
+		t.Errorf("identifying fields not replaced: %+v", u)
+	}
+	if u.AvatarURL != nil || u.Locale != nil || u.LastLoginAt != nil {
+		t.Errorf("optional personal fields should be cleared: %+v", u)
+	}
+}
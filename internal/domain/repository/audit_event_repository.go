@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"seat-management-backend/internal/domain/entity"
+	"seat-management-backend/pkg/audit"
+	"seat-management-backend/pkg/pagination"
+)
+
+// AuditEventFilter は監査ログの検索条件
+type AuditEventFilter struct {
+	ActorType  audit.ActorType
+	ActorID    string
+	Action     entity.AuditAction
+	TargetType string
+	TargetID   string
+	From       *time.Time
+	To         *time.Time
+}
+
+type AuditEventRepository interface {
+	Create(ctx context.Context, event *entity.AuditEvent) error
+	// List は新しい順に監査ログを取得する
+	List(ctx context.Context, filter AuditEventFilter, cursor string, limit int) (*pagination.Page[*entity.AuditEvent], error)
+	Count(ctx context.Context, filter AuditEventFilter) (int64, error)
+	// ScrubActor はユーザーactorIDが操作した記録から個人を特定できる値を消す
+	// actor_idはreplacementに置き換え、IPアドレスとセッションIDは消去する
+	// 追記専用のトリガーが許可するのは、削除済みで個人情報を消去する前のユーザーの墓標のID（deleted_<id>）への置き換えだけ
+	ScrubActor(ctx context.Context, actorID, replacement string) error
+}
@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"seat-management-backend/internal/domain/entity"
+)
+
+type DataExportRepository interface {
+	Create(ctx context.Context, export *entity.DataExport) error
+	// FindByID はユーザーのエクスポートを取得する（他のユーザーのものはErrDataExportNotFound）
+	FindByID(ctx context.Context, userID, id string) (*entity.DataExport, error)
+	// FindActiveByUserID は処理待ち・処理中のエクスポートを取得する
+	FindActiveByUserID(ctx context.Context, userID string) (*entity.DataExport, error)
+	// ClaimNext は処理待ちのエクスポートを1件取り出して処理中にする
+	// staleAfterより前に処理を始めたまま終わっていないもの（ワーカーの異常終了など）も対象にする
+	// 対象がない場合はnilを返す
+	ClaimNext(ctx context.Context, staleAfter time.Duration) (*entity.DataExport, error)
+	// UpdateStatus はステータスと結果（object_key, size_bytes, error, completed_at, expires_at）を保存する
+	// 保存されているステータスがfromのまま（処理中の場合は開始時刻も同じまま）の場合だけ更新し、
+	// 別のワーカーが取り直したなど先に変更されていた場合はErrVersionConflictを返す
+	UpdateStatus(ctx context.Context, export *entity.DataExport, from entity.DataExportStatus) error
+	// ListExpired はexpiresAtを過ぎた完了済みのエクスポートを取得する
+	ListExpired(ctx context.Context, now time.Time, limit int) ([]*entity.DataExport, error)
+	ListByUserID(ctx context.Context, userID string) ([]*entity.DataExport, error)
+	DeleteByUserID(ctx context.Context, userID string) error
+}
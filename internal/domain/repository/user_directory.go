@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+
+	"seat-management-backend/internal/domain/entity"
+)
+
+// UserDirectory は外部認証基盤（Clerk）に登録されたユーザーを取得する
+type UserDirectory interface {
+	// ListUsers はoffsetからlimit件のユーザーを返す。返却件数がlimit未満なら最終ページ
+	ListUsers(ctx context.Context, offset, limit int) ([]*entity.User, error)
+}
@@ -2,16 +2,37 @@ package repository
 
 import (
 	"context"
+	"time"
+
 	"seat-management-backend/internal/domain/entity"
+	"seat-management-backend/pkg/pagination"
 )
 
+// UserFilter はユーザー一覧の検索条件
+type UserFilter struct {
+	Name           string
+	Email          string
+	AuthProvider   entity.AuthProvider
+	LastLoginFrom  *time.Time
+	LastLoginTo    *time.Time
+	IncludeDeleted bool
+}
+
 type UserRepository interface {
 	Create(ctx context.Context, user *entity.User) error
 	FindByID(ctx context.Context, id string) (*entity.User, error)
 	FindByClerkUserID(ctx context.Context, clerkUserID string) (*entity.User, error)
 	FindByEmail(ctx context.Context, email string) (*entity.User, error)
-	Update(ctx context.Context, user *entity.User) error
+	// Update はfieldsで指定したカラムだけを更新し、バージョンを1増やす（fieldsが空でもバージョンは増やす）
+	// user.Versionが現在のバージョンと異なる場合はErrVersionConflictを返す
+	Update(ctx context.Context, user *entity.User, fields ...string) error
 	UpdateLastLogin(ctx context.Context, userID string) error
+	UpdateSuspendedAt(ctx context.Context, userID string, suspendedAt *time.Time) error
 	Delete(ctx context.Context, id string) error
-	List(ctx context.Context, limit, offset int) ([]*entity.User, error)
+	Restore(ctx context.Context, id string) error
+	// ListPendingAnonymization はbeforeより前に削除され、個人情報が残っているユーザーを取得する
+	ListPendingAnonymization(ctx context.Context, before time.Time, limit int) ([]*entity.User, error)
+	// Anonymize はuser.Anonymizeで消去した項目を保存する（削除済みのユーザーが対象）
+	Anonymize(ctx context.Context, user *entity.User) error
+	List(ctx context.Context, filter UserFilter, cursor string, limit int) (*pagination.Page[*entity.User], error)
 }
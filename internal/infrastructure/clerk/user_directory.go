@@ -0,0 +1,70 @@
+package clerk
+
+import (
+	"context"
+
+	clerksdk "github.com/clerk/clerk-sdk-go/v2"
+	"github.com/clerk/clerk-sdk-go/v2/user"
+
+	"seat-management-backend/internal/domain/entity"
+	"seat-management-backend/internal/domain/repository"
+)
+
+type userDirectory struct{}
+
+// NewUserDirectory はClerk Backend APIを使うUserDirectoryを返す
+// 事前にmiddleware.InitClerkでシークレットキーを設定しておく必要がある
+func NewUserDirectory() repository.UserDirectory {
+	return &userDirectory{}
+}
+
+func (d *userDirectory) ListUsers(ctx context.Context, offset, limit int) ([]*entity.User, error) {
+	params := &user.ListParams{}
+	params.Offset = clerksdk.Int64(int64(offset))
+	params.Limit = clerksdk.Int64(int64(limit))
+	params.OrderBy = clerksdk.String("+created_at")
+
+	list, err := user.List(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]*entity.User, 0, len(list.Users))
+	for _, cu := range list.Users {
+		users = append(users, toEntity(cu))
+	}
+	return users, nil
+}
+
+// toEntity はClerkのユーザーをUserエンティティに変換する
+func toEntity(cu *clerksdk.User) *entity.User {
+	email := primaryEmail(cu)
+
+	authProvider := entity.AuthProviderUnknown
+	if len(cu.ExternalAccounts) > 0 {
+		authProvider = entity.AuthProviderFromOAuth(cu.ExternalAccounts[0].Provider)
+	} else if cu.PasswordEnabled {
+		authProvider = entity.AuthProviderEmail
+	}
+
+	return &entity.User{
+		ClerkUserID:           cu.ID,
+		Email:                 email,
+		Name:                  entity.BuildDisplayName(cu.FirstName, cu.LastName, email),
+		AvatarURL:             cu.ImageURL,
+		DefaultPrivacySetting: entity.PrivacyPrivate,
+		PrimaryAuthProvider:   authProvider,
+	}
+}
+
+func primaryEmail(cu *clerksdk.User) string {
+	for _, e := range cu.EmailAddresses {
+		if cu.PrimaryEmailAddressID != nil && e.ID == *cu.PrimaryEmailAddressID {
+			return e.EmailAddress
+		}
+	}
+	if len(cu.EmailAddresses) > 0 {
+		return cu.EmailAddresses[0].EmailAddress
+	}
+	return ""
+}
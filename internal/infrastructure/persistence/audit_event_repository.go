@@ -0,0 +1,91 @@
+package persistence
+
+import (
+	"context"
+
+	"seat-management-backend/internal/domain/entity"
+	"seat-management-backend/internal/domain/repository"
+	"seat-management-backend/pkg/audit"
+	"seat-management-backend/pkg/database"
+	"seat-management-backend/pkg/pagination"
+
+	"gorm.io/gorm"
+)
+
+type auditEventRepository struct {
+	db *gorm.DB
+}
+
+// NewAuditEventRepository はAuditEventRepositoryの実装を返す
+func NewAuditEventRepository(db *gorm.DB) repository.AuditEventRepository {
+	return &auditEventRepository{db: db}
+}
+
+func (r *auditEventRepository) Create(ctx context.Context, event *entity.AuditEvent) error {
+	return r.db.WithContext(ctx).Create(event).Error
+}
+
+// ScrubActor は追記専用のトリガーが例外として許可する更新だけを行う
+func (r *auditEventRepository) ScrubActor(ctx context.Context, actorID, replacement string) error {
+	return r.db.WithContext(ctx).
+		Model(&entity.AuditEvent{}).
+		Where("actor_type = ? AND actor_id = ?", audit.ActorUser, actorID).
+		Updates(map[string]any{
+			"actor_id":   replacement,
+			"ip_address": nil,
+			"session_id": nil,
+		}).Error
+}
+
+func (r *auditEventRepository) List(ctx context.Context, filter repository.AuditEventFilter, cursor string, limit int) (*pagination.Page[*entity.AuditEvent], error) {
+	afterID, err := pagination.DecodeCursor(cursor)
+	if err != nil {
+		return nil, entity.ErrInvalidCursor
+	}
+
+	// 検索やCSV出力は件数が多くなり得るため読み取りレプリカに振り分ける
+	query := database.ReadReplica(r.db.WithContext(ctx))
+	if afterID != "" {
+		query = query.Where("id < ?", afterID)
+	}
+	query = filterAuditEvents(query, filter)
+
+	// ULIDは時系列順にソート可能なため、IDの降順で新しい順になる
+	var events []*entity.AuditEvent
+	if err := query.Limit(limit + 1).Order("id DESC").Find(&events).Error; err != nil {
+		return nil, err
+	}
+
+	return pagination.NewPage(events, limit, func(e *entity.AuditEvent) string { return e.ID }), nil
+}
+
+func (r *auditEventRepository) Count(ctx context.Context, filter repository.AuditEventFilter) (int64, error) {
+	var count int64
+	err := filterAuditEvents(database.ReadReplica(r.db.WithContext(ctx)).Model(&entity.AuditEvent{}), filter).Count(&count).Error
+	return count, err
+}
+
+func filterAuditEvents(query *gorm.DB, filter repository.AuditEventFilter) *gorm.DB {
+	if filter.ActorType != "" {
+		query = query.Where("actor_type = ?", filter.ActorType)
+	}
+	if filter.ActorID != "" {
+		query = query.Where("actor_id = ?", filter.ActorID)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.TargetType != "" {
+		query = query.Where("target_type = ?", filter.TargetType)
+	}
+	if filter.TargetID != "" {
+		query = query.Where("target_id = ?", filter.TargetID)
+	}
+	if filter.From != nil {
+		query = query.Where("occurred_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("occurred_at < ?", *filter.To)
+	}
+	return query
+}
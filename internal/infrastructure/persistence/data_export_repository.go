@@ -0,0 +1,119 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"seat-management-backend/internal/domain/entity"
+	"seat-management-backend/internal/domain/repository"
+
+	"gorm.io/gorm"
+)
+
+type dataExportRepository struct {
+	db *gorm.DB
+}
+
+// NewDataExportRepository はDataExportRepositoryの実装を返す
+func NewDataExportRepository(db *gorm.DB) repository.DataExportRepository {
+	return &dataExportRepository{db: db}
+}
+
+func (r *dataExportRepository) Create(ctx context.Context, export *entity.DataExport) error {
+	return r.db.WithContext(ctx).Create(export).Error
+}
+
+func (r *dataExportRepository) FindByID(ctx context.Context, userID, id string) (*entity.DataExport, error) {
+	var export entity.DataExport
+	err := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).First(&export).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entity.ErrDataExportNotFound
+		}
+		return nil, err
+	}
+	return &export, nil
+}
+
+func (r *dataExportRepository) FindActiveByUserID(ctx context.Context, userID string) (*entity.DataExport, error) {
+	var export entity.DataExport
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND status IN ?", userID, []entity.DataExportStatus{entity.DataExportPending, entity.DataExportProcessing}).
+		Order("created_at DESC").
+		First(&export).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entity.ErrDataExportNotFound
+		}
+		return nil, err
+	}
+	return &export, nil
+}
+
+func (r *dataExportRepository) ClaimNext(ctx context.Context, staleAfter time.Duration) (*entity.DataExport, error) {
+	now := time.Now()
+	// 複数のワーカーが同じジョブを取り出さないよう、ロック中の行は飛ばす
+	var exports []*entity.DataExport
+	err := r.db.WithContext(ctx).Raw(`
+		UPDATE data_exports SET status = ?, started_at = ?, updated_at = ?
+		WHERE id = (
+			SELECT id FROM data_exports
+			WHERE status = ? OR (status = ? AND started_at < ?)
+			ORDER BY created_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`,
+		entity.DataExportProcessing, now, now,
+		entity.DataExportPending, entity.DataExportProcessing, now.Add(-staleAfter),
+	).Scan(&exports).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(exports) == 0 {
+		return nil, nil
+	}
+	return exports[0], nil
+}
+
+func (r *dataExportRepository) UpdateStatus(ctx context.Context, export *entity.DataExport, from entity.DataExportStatus) error {
+	result := r.db.WithContext(ctx).
+		Model(&entity.DataExport{}).
+		Where("id = ? AND status = ? AND started_at IS NOT DISTINCT FROM ?", export.ID, from, export.StartedAt).
+		Updates(map[string]any{
+			"status":       export.Status,
+			"object_key":   export.ObjectKey,
+			"size_bytes":   export.SizeBytes,
+			"error":        export.Error,
+			"completed_at": export.CompletedAt,
+			"expires_at":   export.ExpiresAt,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return entity.ErrVersionConflict
+	}
+	return nil
+}
+
+func (r *dataExportRepository) ListExpired(ctx context.Context, now time.Time, limit int) ([]*entity.DataExport, error) {
+	var exports []*entity.DataExport
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND expires_at < ?", entity.DataExportCompleted, now).
+		Order("expires_at").
+		Limit(limit).
+		Find(&exports).Error
+	return exports, err
+}
+
+func (r *dataExportRepository) ListByUserID(ctx context.Context, userID string) ([]*entity.DataExport, error) {
+	var exports []*entity.DataExport
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at").Find(&exports).Error
+	return exports, err
+}
+
+func (r *dataExportRepository) DeleteByUserID(ctx context.Context, userID string) error {
+	return r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&entity.DataExport{}).Error
+}
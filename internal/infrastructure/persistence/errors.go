@@ -0,0 +1,31 @@
+package persistence
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"seat-management-backend/internal/domain/entity"
+)
+
+// uniqueViolation はPostgreSQLの一意制約違反のエラーコード
+const uniqueViolation = "23505"
+
+// uniqueConstraintErrors は一意制約（インデックス）名と対応するドメインエラー
+var uniqueConstraintErrors = map[string]error{
+	"idx_users_email":    entity.ErrDuplicateEmail,
+	"idx_users_clerk_id": entity.ErrDuplicateClerkID,
+}
+
+// translateError はデータベースのエラーをドメインエラーに変換する
+// 対応するものがない場合は元のエラーをそのまま返す
+func translateError(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) || pgErr.Code != uniqueViolation {
+		return err
+	}
+	if domainErr, ok := uniqueConstraintErrors[pgErr.ConstraintName]; ok {
+		return domainErr
+	}
+	return err
+}
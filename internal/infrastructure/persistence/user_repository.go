@@ -7,6 +7,8 @@ import (
 
 	"seat-management-backend/internal/domain/entity"
 	"seat-management-backend/internal/domain/repository"
+	"seat-management-backend/pkg/database"
+	"seat-management-backend/pkg/pagination"
 
 	"gorm.io/gorm"
 )
@@ -21,7 +23,7 @@ func NewUserRepository(db *gorm.DB) repository.UserRepository {
 }
 
 func (r *userRepository) Create(ctx context.Context, user *entity.User) error {
-	return r.db.WithContext(ctx).Create(user).Error
+	return translateError(r.db.WithContext(ctx).Create(user).Error)
 }
 
 func (r *userRepository) FindByID(ctx context.Context, id string) (*entity.User, error) {
@@ -60,8 +62,8 @@ func (r *userRepository) FindByEmail(ctx context.Context, email string) (*entity
 	return &user, nil
 }
 
-func (r *userRepository) Update(ctx context.Context, user *entity.User) error {
-	return r.db.WithContext(ctx).Save(user).Error
+func (r *userRepository) Update(ctx context.Context, user *entity.User, fields ...string) error {
+	return updateVersioned(ctx, r.db, user, user.ID, &user.Version, fields, entity.ErrUserNotFound)
 }
 
 func (r *userRepository) UpdateLastLogin(ctx context.Context, userID string) error {
@@ -72,16 +74,126 @@ func (r *userRepository) UpdateLastLogin(ctx context.Context, userID string) err
 		Error
 }
 
+func (r *userRepository) UpdateSuspendedAt(ctx context.Context, userID string, suspendedAt *time.Time) error {
+	result := r.db.WithContext(ctx).
+		Model(&entity.User{}).
+		Where("id = ?", userID).
+		Updates(map[string]any{
+			"suspended_at": suspendedAt,
+			"version":      gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return entity.ErrUserNotFound
+	}
+	return nil
+}
+
 func (r *userRepository) Delete(ctx context.Context, id string) error {
 	return r.db.WithContext(ctx).Delete(&entity.User{}, "id = ?", id).Error
 }
 
-func (r *userRepository) List(ctx context.Context, limit, offset int) ([]*entity.User, error) {
+func (r *userRepository) Restore(ctx context.Context, id string) error {
+	var user entity.User
+	err := r.db.WithContext(ctx).Unscoped().Where("id = ?", id).First(&user).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entity.ErrUserNotFound
+		}
+		return err
+	}
+	if !user.DeletedAt.Valid {
+		return entity.ErrUserNotDeleted
+	}
+	if user.IsAnonymized() {
+		return entity.ErrUserAnonymized
+	}
+
+	// 削除後に同じメールアドレスで登録されている場合は一意制約に違反する
+	return translateError(r.db.WithContext(ctx).
+		Unscoped().
+		Model(&entity.User{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"deleted_at": nil,
+			"version":    gorm.Expr("version + 1"),
+		}).
+		Error)
+}
+
+func (r *userRepository) ListPendingAnonymization(ctx context.Context, before time.Time, limit int) ([]*entity.User, error) {
 	var users []*entity.User
 	err := r.db.WithContext(ctx).
+		Unscoped().
+		Where("deleted_at < ? AND anonymized_at IS NULL", before).
+		Order("deleted_at").
 		Limit(limit).
-		Offset(offset).
-		Order("created_at DESC").
 		Find(&users).Error
 	return users, err
 }
+
+func (r *userRepository) Anonymize(ctx context.Context, user *entity.User) error {
+	result := r.db.WithContext(ctx).
+		Unscoped().
+		Model(&entity.User{}).
+		Where("id = ? AND deleted_at IS NOT NULL AND anonymized_at IS NULL", user.ID).
+		Updates(map[string]any{
+			"clerk_user_id": user.ClerkUserID,
+			"email":         user.Email,
+			"name":          user.Name,
+			"avatar_url":    user.AvatarURL,
+			"locale":        user.Locale,
+			"last_login_at": user.LastLoginAt,
+			"anonymized_at": user.AnonymizedAt,
+			"version":       gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return entity.ErrUserNotFound
+	}
+	user.Version++
+	return nil
+}
+
+func (r *userRepository) List(ctx context.Context, filter repository.UserFilter, cursor string, limit int) (*pagination.Page[*entity.User], error) {
+	afterID, err := pagination.DecodeCursor(cursor)
+	if err != nil {
+		return nil, entity.ErrInvalidCursor
+	}
+
+	// 管理画面の検索は重いため読み取りレプリカに振り分ける
+	query := database.ReadReplica(r.db.WithContext(ctx))
+	if afterID != "" {
+		query = query.Where("id < ?", afterID)
+	}
+	if filter.IncludeDeleted {
+		query = query.Unscoped()
+	}
+	if filter.Name != "" {
+		query = query.Where("name ILIKE ?", "%"+filter.Name+"%")
+	}
+	if filter.Email != "" {
+		query = query.Where("email ILIKE ?", "%"+filter.Email+"%")
+	}
+	if filter.AuthProvider != "" {
+		query = query.Where("primary_auth_provider = ?", filter.AuthProvider)
+	}
+	if filter.LastLoginFrom != nil {
+		query = query.Where("last_login_at >= ?", *filter.LastLoginFrom)
+	}
+	if filter.LastLoginTo != nil {
+		query = query.Where("last_login_at < ?", *filter.LastLoginTo)
+	}
+
+	// ULIDは時系列順にソート可能なため、IDの降順で新しい順になる
+	var users []*entity.User
+	if err := query.Limit(limit + 1).Order("id DESC").Find(&users).Error; err != nil {
+		return nil, err
+	}
+
+	return pagination.NewPage(users, limit, func(u *entity.User) string { return u.ID }), nil
+}
@@ -0,0 +1,43 @@
+package persistence
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"seat-management-backend/internal/domain/entity"
+)
+
+// updateVersioned は楽観的ロックでmodelのfieldsで指定したカラムだけを更新する
+// WHERE句に現在のバージョンを含め、成功した場合は*versionを1増やす
+// 更新件数が0件の場合、レコードがなければnotFound、あればErrVersionConflictを返す
+// fieldsが空でもバージョンの確認と更新は行う（If-Matchの確認を素通りさせない）
+// modelは主キー（id）が設定されたエンティティのポインタで、versionはそのVersionフィールドを指すこと
+func updateVersioned(ctx context.Context, db *gorm.DB, model any, id string, version *int64, fields []string, notFound error) error {
+	expected := *version
+	*version = expected + 1
+	columns := append(append([]string{}, fields...), "version", "updated_at")
+
+	result := db.WithContext(ctx).
+		Model(model).
+		Where("version = ?", expected).
+		Select(columns).
+		Updates(model)
+	if result.Error != nil {
+		*version = expected
+		return translateError(result.Error)
+	}
+	if result.RowsAffected > 0 {
+		return nil
+	}
+
+	*version = expected
+	var count int64
+	if err := db.WithContext(ctx).Model(model).Where("id = ?", id).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return notFound
+	}
+	return entity.ErrVersionConflict
+}
@@ -0,0 +1,54 @@
+package persistence
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"seat-management-backend/internal/domain/entity"
+)
+
+// newDryRunDB はDBに接続せず、組み立てたUPDATE文を記録するgorm.DBを作成する
+func newDryRunDB(t *testing.T) (*gorm.DB, *[]string) {
+	t.Helper()
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=127.0.0.1 user=test dbname=test sslmode=disable"}), &gorm.Config{
+		DryRun:                 true,
+		DisableAutomaticPing:   true,
+		SkipDefaultTransaction: true,
+	})
+	if err != nil {
+		t.Fatalf("gorm.Open: %v", err)
+	}
+	var updates []string
+	err = db.Callback().Update().After("gorm:update").Register("test:capture", func(tx *gorm.DB) {
+		updates = append(updates, tx.Statement.SQL.String())
+	})
+	if err != nil {
+		t.Fatalf("register callback: %v", err)
+	}
+	return db, &updates
+}
+
+func TestUpdateVersionedChecksVersionWithoutFields(t *testing.T) {
+	db, updates := newDryRunDB(t)
+	user := &entity.User{ID: "01J00000000000000000000001", Version: 3}
+
+	// DryRunでは更新件数が0件になるため、存在確認の結果としてnotFoundが返る
+	_ = updateVersioned(context.Background(), db, user, user.ID, &user.Version, nil, entity.ErrUserNotFound)
+
+	if len(*updates) != 1 {
+		t.Fatalf("issued %d UPDATE statements, want 1", len(*updates))
+	}
+	sql := (*updates)[0]
+	for _, want := range []string{`"version"=`, `"updated_at"=`, "version = $"} {
+		if !strings.Contains(sql, want) {
+			t.Errorf("UPDATE %q does not contain %q", sql, want)
+		}
+	}
+	if user.Version != 3 {
+		t.Errorf("version = %d, want it restored to 3 after the failed update", user.Version)
+	}
+}
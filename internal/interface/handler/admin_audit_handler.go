@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"seat-management-backend/internal/domain/entity"
+	"seat-management-backend/internal/domain/repository"
+	"seat-management-backend/internal/middleware"
+	"seat-management-backend/internal/usecase"
+	"seat-management-backend/pkg/audit"
+)
+
+type AdminAuditHandler struct {
+	auditUsecase usecase.AuditUsecase
+	userUsecase  usecase.UserUsecase
+	adminOrgID   string
+}
+
+// AuditEventQuery は監査ログの検索条件のクエリパラメータ
+type AuditEventQuery struct {
+	ActorType  string    `form:"actor_type" binding:"omitempty,oneof=user webhook system"`
+	ActorID    string    `form:"actor_id" binding:"max=255"`
+	Action     string    `form:"action" binding:"max=100"`
+	TargetType string    `form:"target_type" binding:"max=50"`
+	TargetID   string    `form:"target_id" binding:"max=255"`
+	From       time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To         time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+// ListAuditEventsRequest は監査ログ一覧のクエリパラメータ
+type ListAuditEventsRequest struct {
+	AuditEventQuery
+	PageQuery
+}
+
+// NewAdminAuditHandler は組織adminOrgIDの管理者だけが使えるハンドラーを作成
+func NewAdminAuditHandler(au usecase.AuditUsecase, uu usecase.UserUsecase, adminOrgID string) *AdminAuditHandler {
+	return &AdminAuditHandler{
+		auditUsecase: au,
+		userUsecase:  uu,
+		adminOrgID:   adminOrgID,
+	}
+}
+
+func (q *AuditEventQuery) filter() repository.AuditEventFilter {
+	filter := repository.AuditEventFilter{
+		ActorType:  audit.ActorType(q.ActorType),
+		ActorID:    q.ActorID,
+		Action:     entity.AuditAction(q.Action),
+		TargetType: q.TargetType,
+		TargetID:   q.TargetID,
+	}
+	if !q.From.IsZero() {
+		filter.From = &q.From
+	}
+	if !q.To.IsZero() {
+		filter.To = &q.To
+	}
+	return filter
+}
+
+// 監査ログの検索
+func (h *AdminAuditHandler) ListEvents(c *gin.Context) {
+	var req ListAuditEventsRequest
+	if !bindQuery(c, &req) {
+		return
+	}
+
+	page, err := h.auditUsecase.List(c.Request.Context(), req.filter(), req.Cursor, req.Limit)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// 監査ログのCSV出力
+func (h *AdminAuditHandler) ExportEvents(c *gin.Context) {
+	var req AuditEventQuery
+	if !bindQuery(c, &req) {
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", attachment("audit-events-"+time.Now().Format("20060102")+".csv"))
+
+	// 件数が多くてもメモリに載せないよう、1ページずつレスポンスに書き込む
+	err := h.auditUsecase.WriteCSV(c.Request.Context(), req.filter(), newStreamWriter(c))
+	if err == nil {
+		return
+	}
+	if !c.Writer.Written() {
+		// まだ何も送っていなければ通常のエラーレスポンスにする
+		c.Writer.Header().Del("Content-Type")
+		c.Writer.Header().Del("Content-Disposition")
+		_ = c.Error(err)
+		return
+	}
+
+	// 途中までのCSVが完全なものと誤解されないよう、終端を送らずに接続を切る
+	slog.ErrorContext(c.Request.Context(), "audit: CSV export aborted", "error", err)
+	panic(http.ErrAbortHandler)
+}
+
+// RegisterRoutes は管理者用の監査ログのルートを登録
+func (h *AdminAuditHandler) RegisterRoutes(r *gin.Engine) {
+	admin := r.Group("/api/admin/audit-events")
+	admin.Use(
+		middleware.ClerkAuthMiddleware(),
+		middleware.RequireOrganizationRole(h.adminOrgID, middleware.OrgRoleAdmin),
+		middleware.RequireActiveUser(h.userUsecase),
+	)
+	{
+		admin.GET("", h.ListEvents)
+		admin.GET("/export", h.ExportEvents)
+	}
+}
@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"seat-management-backend/internal/domain/entity"
+	"seat-management-backend/internal/domain/repository"
+	"seat-management-backend/internal/middleware"
+	"seat-management-backend/internal/usecase"
+)
+
+type AdminUserHandler struct {
+	userUsecase usecase.UserUsecase
+	adminOrgID  string
+}
+
+// ListUsersRequest は管理者向けユーザー検索のクエリパラメータ
+type ListUsersRequest struct {
+	Name           string    `form:"name" binding:"max=100"`
+	Email          string    `form:"email" binding:"max=255"`
+	AuthProvider   string    `form:"auth_provider" binding:"omitempty,auth_provider"`
+	LastLoginFrom  time.Time `form:"last_login_from" time_format:"2006-01-02T15:04:05Z07:00"`
+	LastLoginTo    time.Time `form:"last_login_to" time_format:"2006-01-02T15:04:05Z07:00"`
+	IncludeDeleted bool      `form:"include_deleted"`
+	PageQuery
+}
+
+// NewAdminUserHandler は組織adminOrgIDの管理者だけが使えるハンドラーを作成
+func NewAdminUserHandler(uu usecase.UserUsecase, adminOrgID string) *AdminUserHandler {
+	return &AdminUserHandler{
+		userUsecase: uu,
+		adminOrgID:  adminOrgID,
+	}
+}
+
+// ユーザー一覧の検索
+func (h *AdminUserHandler) ListUsers(c *gin.Context) {
+	var req ListUsersRequest
+	if !bindQuery(c, &req) {
+		return
+	}
+
+	filter := repository.UserFilter{
+		Name:           req.Name,
+		Email:          req.Email,
+		AuthProvider:   entity.AuthProvider(req.AuthProvider),
+		IncludeDeleted: req.IncludeDeleted,
+	}
+	if !req.LastLoginFrom.IsZero() {
+		filter.LastLoginFrom = &req.LastLoginFrom
+	}
+	if !req.LastLoginTo.IsZero() {
+		filter.LastLoginTo = &req.LastLoginTo
+	}
+
+	page, err := h.userUsecase.List(c.Request.Context(), filter, req.Cursor, req.Limit)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// ユーザー詳細の取得
+func (h *AdminUserHandler) GetUser(c *gin.Context) {
+	user, err := h.userUsecase.GetByID(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	setETag(c, user.Version)
+	c.JSON(http.StatusOK, user)
+}
+
+// ユーザーの利用停止
+func (h *AdminUserHandler) SuspendUser(c *gin.Context) {
+	h.applyAndRespond(c, h.userUsecase.Suspend)
+}
+
+// ユーザーの利用停止解除
+func (h *AdminUserHandler) UnsuspendUser(c *gin.Context) {
+	h.applyAndRespond(c, h.userUsecase.Unsuspend)
+}
+
+// 削除済みユーザーの復元
+func (h *AdminUserHandler) RestoreUser(c *gin.Context) {
+	h.applyAndRespond(c, h.userUsecase.Restore)
+}
+
+// applyAndRespond はユーザーに操作を適用し、更新後のユーザーを返す
+func (h *AdminUserHandler) applyAndRespond(c *gin.Context, apply func(ctx context.Context, id string) error) {
+	id := c.Param("id")
+	if err := apply(c.Request.Context(), id); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	user, err := h.userUsecase.GetByID(c.Request.Context(), id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	setETag(c, user.Version)
+	c.JSON(http.StatusOK, user)
+}
+
+// RegisterRoutes は管理者用ユーザールートを登録
+func (h *AdminUserHandler) RegisterRoutes(r *gin.Engine) {
+	admin := r.Group("/api/admin/users")
+	admin.Use(
+		middleware.ClerkAuthMiddleware(),
+		middleware.RequireOrganizationRole(h.adminOrgID, middleware.OrgRoleAdmin),
+		middleware.RequireActiveUser(h.userUsecase),
+	)
+	{
+		admin.GET("", h.ListUsers)
+		admin.GET("/:id", h.GetUser)
+		admin.POST("/:id/suspend", h.SuspendUser)
+		admin.POST("/:id/unsuspend", h.UnsuspendUser)
+		admin.POST("/:id/restore", h.RestoreUser)
+	}
+}
@@ -0,0 +1,24 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// bindJSON はリクエストボディをバインドし、bindingタグで検証する
+// 失敗した場合はErrorHandlerが422（構文エラーは400）で返すためのエラーを積んでfalseを返す
+func bindJSON(c *gin.Context, obj any) bool {
+	if err := c.ShouldBindJSON(obj); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return false
+	}
+	return true
+}
+
+// bindQuery はクエリパラメータをバインドし、bindingタグで検証する
+func bindQuery(c *gin.Context, obj any) bool {
+	if err := c.ShouldBindQuery(obj); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"seat-management-backend/internal/domain/entity"
+	"seat-management-backend/internal/middleware"
+	"seat-management-backend/internal/usecase"
+)
+
+type DataExportHandler struct {
+	userUsecase       usecase.UserUsecase
+	dataExportUsecase usecase.DataExportUsecase
+}
+
+func NewDataExportHandler(uu usecase.UserUsecase, du usecase.DataExportUsecase) *DataExportHandler {
+	return &DataExportHandler{
+		userUsecase:       uu,
+		dataExportUsecase: du,
+	}
+}
+
+// 個人データのエクスポート
+// データが少ない場合はその場でアーカイブ（ZIP）を返し、多い場合は非同期ジョブを作成して202を返す
+func (h *DataExportHandler) Export(c *gin.Context) {
+	user, ok := currentUser(c, h.userUsecase)
+	if !ok {
+		return
+	}
+	ctx := c.Request.Context()
+
+	async, err := h.dataExportUsecase.RequiresAsync(ctx, user)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	if async {
+		h.requestAsync(c, user)
+		return
+	}
+
+	// 途中で失敗した場合にエラーを返せるよう、書き込む前にすべて作成する
+	var buf bytes.Buffer
+	if err := h.dataExportUsecase.WriteArchive(ctx, user, &buf); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	slog.InfoContext(ctx, "data exported", "user_id", user.ID, "size", buf.Len())
+	c.Header("Cache-Control", "no-store")
+	c.Header("Content-Disposition", attachment(exportFileName(time.Now())))
+	c.Data(http.StatusOK, usecase.DataExportContentType, buf.Bytes())
+}
+
+// 非同期ジョブでのエクスポートの作成
+func (h *DataExportHandler) CreateExport(c *gin.Context) {
+	user, ok := currentUser(c, h.userUsecase)
+	if !ok {
+		return
+	}
+	h.requestAsync(c, user)
+}
+
+func (h *DataExportHandler) requestAsync(c *gin.Context, user *entity.User) {
+	export, err := h.dataExportUsecase.Request(c.Request.Context(), user)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Header("Location", "/api/users/me/exports/"+export.ID)
+	c.JSON(http.StatusAccepted, export)
+}
+
+// エクスポートの状態の取得
+func (h *DataExportHandler) GetExport(c *gin.Context) {
+	user, ok := currentUser(c, h.userUsecase)
+	if !ok {
+		return
+	}
+
+	export, err := h.dataExportUsecase.Get(c.Request.Context(), user.ID, c.Param("id"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, export)
+}
+
+// 完了したエクスポートのダウンロード
+func (h *DataExportHandler) DownloadExport(c *gin.Context) {
+	user, ok := currentUser(c, h.userUsecase)
+	if !ok {
+		return
+	}
+
+	rc, export, err := h.dataExportUsecase.Open(c.Request.Context(), user.ID, c.Param("id"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	defer rc.Close()
+
+	c.Header("Cache-Control", "no-store")
+	c.Header("Content-Disposition", attachment(exportFileName(*export.CompletedAt)))
+	c.Header("Content-Type", usecase.DataExportContentType)
+	if export.SizeBytes != nil {
+		c.Header("Content-Length", strconv.FormatInt(*export.SizeBytes, 10))
+	}
+	c.Status(http.StatusOK)
+	if _, err := copyStream(c, rc); err != nil {
+		// ヘッダーは送信済みのため、ログに残すだけにする
+		slog.WarnContext(c.Request.Context(), "failed to send data export", "export_id", export.ID, "error", err)
+	}
+}
+
+func exportFileName(t time.Time) string {
+	return "personal-data-" + t.Format("20060102") + ".zip"
+}
+
+func attachment(filename string) string {
+	return fmt.Sprintf(`attachment; filename="%s"`, filename)
+}
+
+// RegisterRoutes は個人データのエクスポートのルートを登録
+func (h *DataExportHandler) RegisterRoutes(r *gin.Engine) {
+	me := r.Group("/api/users/me")
+	me.Use(middleware.ClerkAuthMiddleware())
+	{
+		me.GET("/export", h.Export)
+		me.POST("/exports", h.CreateExport)
+		me.GET("/exports/:id", h.GetExport)
+		me.GET("/exports/:id/download", h.DownloadExport)
+	}
+}
@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"seat-management-backend/internal/domain/entity"
+	"seat-management-backend/internal/middleware"
+	"seat-management-backend/internal/usecase"
+)
+
+// fakeDataExportUsecase は決まった結果を返すDataExportUsecase
+type fakeDataExportUsecase struct {
+	usecase.DataExportUsecase
+	async   bool
+	archive []byte
+	export  *entity.DataExport
+}
+
+func (u *fakeDataExportUsecase) RequiresAsync(context.Context, *entity.User) (bool, error) {
+	return u.async, nil
+}
+
+func (u *fakeDataExportUsecase) WriteArchive(_ context.Context, _ *entity.User, w io.Writer) error {
+	_, err := w.Write(u.archive)
+	return err
+}
+
+func (u *fakeDataExportUsecase) Request(_ context.Context, user *entity.User) (*entity.DataExport, error) {
+	u.export = &entity.DataExport{ID: "01J00000000000000000000042", UserID: user.ID, Status: entity.DataExportPending}
+	return u.export, nil
+}
+
+func (u *fakeDataExportUsecase) Open(_ context.Context, userID, id string) (io.ReadCloser, *entity.DataExport, error) {
+	if u.export == nil || u.export.ID != id || u.export.UserID != userID {
+		return nil, nil, entity.ErrDataExportNotFound
+	}
+	if u.export.Status != entity.DataExportCompleted {
+		return nil, nil, entity.ErrDataExportNotReady
+	}
+	return io.NopCloser(bytes.NewReader(u.archive)), u.export, nil
+}
+
+// newDataExportRouter は認証済みのユーザーとしてリクエストを処理するルーターを作成する
+// Clerkの検証は省略し、コンテキストにユーザーIDを設定する
+func newDataExportRouter(exports *fakeDataExportUsecase) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	const userID = "01J00000000000000000000001"
+	users := &fakeUserUsecase{users: map[string]*entity.User{
+		userID: {ID: userID, ClerkUserID: "user_test", Email: "a@example.com", Name: "A"},
+	}}
+	h := NewDataExportHandler(users, exports)
+
+	r := gin.New()
+	r.Use(middleware.Locale(), middleware.ErrorHandler(), func(c *gin.Context) {
+		c.Set("clerkUserID", "user_test")
+	})
+	r.GET("/api/users/me/export", h.Export)
+	r.POST("/api/users/me/exports", h.CreateExport)
+	r.GET("/api/users/me/exports/:id/download", h.DownloadExport)
+	return r
+}
+
+func serve(r *gin.Engine, method, target string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(method, target, nil))
+	return rec
+}
+
+func TestExportReturnsArchiveForSmallAccounts(t *testing.T) {
+	exports := &fakeDataExportUsecase{archive: []byte("zip")}
+	rec := serve(newDataExportRouter(exports), http.MethodGet, "/api/users/me/export")
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body)
+	}
+	if got := rec.Header().Get("Content-Type"); got != usecase.DataExportContentType {
+		t.Errorf("Content-Type = %s, want %s", got, usecase.DataExportContentType)
+	}
+	if got := rec.Header().Get("Cache-Control"); got != "no-store" {
+		t.Errorf("Cache-Control = %s, want no-store", got)
+	}
+	if rec.Body.String() != "zip" {
+		t.Errorf("body = %q, want the archive", rec.Body)
+	}
+}
+
+func TestExportAcceptsAsyncJob(t *testing.T) {
+	for _, tt := range []struct {
+		name, method, target string
+	}{
+		{name: "large account", method: http.MethodGet, target: "/api/users/me/export"},
+		{name: "explicit request", method: http.MethodPost, target: "/api/users/me/exports"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			exports := &fakeDataExportUsecase{async: true}
+			rec := serve(newDataExportRouter(exports), tt.method, tt.target)
+
+			if rec.Code != http.StatusAccepted {
+				t.Fatalf("status = %d, want 202; body = %s", rec.Code, rec.Body)
+			}
+			if exports.export == nil {
+				t.Fatal("no export job was requested")
+			}
+			if got, want := rec.Header().Get("Location"), "/api/users/me/exports/"+exports.export.ID; got != want {
+				t.Errorf("Location = %q, want %q", got, want)
+			}
+			var body entity.DataExport
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decode body: %v", err)
+			}
+			if body.ID != exports.export.ID || body.Status != entity.DataExportPending {
+				t.Errorf("body = %+v, want the pending export", body)
+			}
+		})
+	}
+}
+
+func TestDownloadExportStreamsArchive(t *testing.T) {
+	// 複数のチャンクに分かれる大きさにする
+	archive := bytes.Repeat([]byte("0123456789"), streamChunkSize/4)
+	exports := &fakeDataExportUsecase{async: true, archive: archive}
+	r := newDataExportRouter(exports)
+	serve(r, http.MethodPost, "/api/users/me/exports")
+	target := "/api/users/me/exports/" + exports.export.ID + "/download"
+
+	if rec := serve(r, http.MethodGet, target); rec.Code != http.StatusConflict {
+		t.Fatalf("download before completion: status = %d, want 409", rec.Code)
+	}
+
+	completedAt := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	size := int64(len(archive))
+	exports.export.Status = entity.DataExportCompleted
+	exports.export.CompletedAt = &completedAt
+	exports.export.SizeBytes = &size
+
+	rec := serve(r, http.MethodGet, target)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body)
+	}
+	if got := rec.Header().Get("Content-Length"); got != strconv.FormatInt(size, 10) {
+		t.Errorf("Content-Length = %s, want %d", got, size)
+	}
+	if got, want := rec.Header().Get("Content-Disposition"), `attachment; filename="personal-data-20261001.zip"`; got != want {
+		t.Errorf("Content-Disposition = %s, want %s", got, want)
+	}
+	if !bytes.Equal(rec.Body.Bytes(), archive) {
+		t.Errorf("body has %d bytes, want the %d byte archive", rec.Body.Len(), len(archive))
+	}
+}
@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// setETag はリソースのバージョンを強いETagとして設定する
+// ETagは編集できる項目の状態を表し、last_login_atなどバージョンを上げない項目の変化では変わらない
+func setETag(c *gin.Context, version int64) {
+	c.Header("ETag", etag(version))
+}
+
+func etag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// ifMatch はIf-Matchヘッダーの前提条件を満たすかを返す（RFC 9110 13.1.1）
+// ヘッダーがない場合と "*" の場合は満たす（対象のリソースは存在するため）
+// それ以外はカンマ区切りのETagのいずれかが強い比較で一致すれば満たす
+// 弱いETag（W/"..."）は強い比較では一致しないため無視し、形式が不正な場合は満たさない
+func ifMatch(c *gin.Context, version int64) bool {
+	header := c.Request.Header.Values("If-Match")
+	if len(header) == 0 {
+		return true
+	}
+
+	want := etag(version)
+	for _, value := range header {
+		tags, ok := parseETagList(value)
+		if !ok {
+			return false
+		}
+		for _, tag := range tags {
+			if tag == "*" || tag == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// parseETagList はカンマ区切りのETagのリストを分割する
+// 各要素は "*"、"..."、W/"..." のいずれかで、値にカンマを含んでもよい
+func parseETagList(value string) ([]string, bool) {
+	var tags []string
+	s := value
+	for {
+		s = strings.TrimLeft(s, " \t,")
+		if s == "" {
+			return tags, true
+		}
+
+		var tag string
+		switch {
+		case s[0] == '*':
+			tag, s = "*", s[1:]
+		case strings.HasPrefix(s, `W/"`), s[0] == '"':
+			start := strings.IndexByte(s, '"')
+			end := strings.IndexByte(s[start+1:], '"')
+			if end < 0 {
+				return nil, false
+			}
+			end += start + 2
+			tag, s = s[:end], s[end:]
+		default:
+			return nil, false
+		}
+
+		s = strings.TrimLeft(s, " \t")
+		if s != "" && s[0] != ',' {
+			return nil, false
+		}
+		tags = append(tags, tag)
+	}
+}
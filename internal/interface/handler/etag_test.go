@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestSetETag(t *testing.T) {
+	rec := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rec)
+	setETag(c, 42)
+
+	if got := rec.Header().Get("ETag"); got != `"42"` {
+		t.Errorf("ETag = %s, want strong tag \"42\"", got)
+	}
+}
+
+func TestIfMatch(t *testing.T) {
+	tests := []struct {
+		name   string
+		header []string
+		want   bool
+	}{
+		{name: "no header", header: nil, want: true},
+		{name: "wildcard", header: []string{"*"}, want: true},
+		{name: "matching tag", header: []string{`"3"`}, want: true},
+		{name: "stale tag", header: []string{`"2"`}, want: false},
+		{name: "weak tag never matches", header: []string{`W/"3"`}, want: false},
+		{name: "list containing the tag", header: []string{`"1", W/"3" ,"3"`}, want: true},
+		{name: "list without the tag", header: []string{`"1", "2"`}, want: false},
+		{name: "tag in a repeated header", header: []string{`"1"`, `"3"`}, want: true},
+		{name: "comma inside a tag", header: []string{`"3,4"`}, want: false},
+		{name: "unquoted", header: []string{`3`}, want: false},
+		{name: "unterminated", header: []string{`"3`}, want: false},
+		{name: "garbage after a tag", header: []string{`"3"x`}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodPatch, "/api/users/me", nil)
+			for _, v := range tt.header {
+				c.Request.Header.Add("If-Match", v)
+			}
+
+			if got := ifMatch(c, 3); got != tt.want {
+				t.Errorf("ifMatch(%q, 3) = %v, want %v", tt.header, got, tt.want)
+			}
+		})
+	}
+}
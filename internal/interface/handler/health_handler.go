@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"seat-management-backend/pkg/database"
+)
+
+const readinessTimeout = 2 * time.Second
+
+type HealthHandler struct {
+	db           *gorm.DB
+	migrator     *database.Migrator
+	shuttingDown atomic.Bool
+}
+
+func NewHealthHandler(db *gorm.DB, migrator *database.Migrator) *HealthHandler {
+	return &HealthHandler{
+		db:       db,
+		migrator: migrator,
+	}
+}
+
+// MarkShuttingDown はシャットダウン開始を記録し、以降のreadinessを失敗させる
+func (h *HealthHandler) MarkShuttingDown() {
+	h.shuttingDown.Store(true)
+}
+
+// Liveness はプロセスが応答できるかだけを返す（依存先は確認しない）
+func (h *HealthHandler) Liveness(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// Readiness はデータベースへの疎通とマイグレーションの適用状況を確認する
+func (h *HealthHandler) Readiness(c *gin.Context) {
+	if h.shuttingDown.Load() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "shutting_down"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), readinessTimeout)
+	defer cancel()
+
+	ready := true
+	resp := gin.H{}
+
+	if err := database.Ping(ctx, h.db); err != nil {
+		slog.WarnContext(ctx, "readiness check: database ping failed", "error", err)
+		ready = false
+		resp["database"] = "unreachable"
+	} else {
+		resp["database"] = "connected"
+
+		if sqlDB, err := h.db.DB(); err == nil {
+			stats := sqlDB.Stats()
+			resp["pool"] = gin.H{
+				"open_connections": stats.OpenConnections,
+				"in_use":           stats.InUse,
+				"idle":             stats.Idle,
+				"wait_count":       stats.WaitCount,
+			}
+		}
+
+		pending, err := h.migrator.Pending(ctx)
+		switch {
+		case err != nil:
+			slog.WarnContext(ctx, "readiness check: migration status failed", "error", err)
+			ready = false
+			resp["migrations"] = "unknown"
+		case pending > 0:
+			ready = false
+			resp["migrations"] = gin.H{"status": "pending", "pending": pending}
+		default:
+			resp["migrations"] = gin.H{"status": "up_to_date", "pending": 0}
+		}
+	}
+
+	if !ready {
+		resp["status"] = "unavailable"
+		c.JSON(http.StatusServiceUnavailable, resp)
+		return
+	}
+	resp["status"] = "ok"
+	c.JSON(http.StatusOK, resp)
+}
+
+// RegisterRoutes はヘルスチェックルートを登録
+func (h *HealthHandler) RegisterRoutes(r *gin.Engine) {
+	r.GET("/health/live", h.Liveness)
+	r.GET("/health/ready", h.Readiness)
+	// 既存の監視設定との互換性のため、/healthはreadinessとして扱う
+	r.GET("/health", h.Readiness)
+}
@@ -0,0 +1,9 @@
+package handler
+
+// PageQuery は一覧系エンドポイント共通のページネーションパラメータ
+// レスポンスは pagination.Page の形式（items, next_cursor）で返す
+type PageQuery struct {
+	Cursor string `form:"cursor"`
+	// 省略時は pagination.DefaultLimit、上限は pagination.MaxLimit
+	Limit int `form:"limit" binding:"omitempty,min=1,max=100"`
+}
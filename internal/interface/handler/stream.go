@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// streamWriterTimeout は1回分（CSVの1ページやダウンロードの1チャンク）を書き込むまでの時間の上限
+// サーバーのWriteTimeoutはレスポンス全体にかかるため、書き込むごとに延長する
+const streamWriterTimeout = 30 * time.Second
+
+// streamChunkSize はファイルのダウンロードを送り出す単位
+const streamChunkSize = 1 << 20
+
+// streamWriter は少しずつ送り出すレスポンスのWriter
+type streamWriter struct {
+	c  *gin.Context
+	rc *http.ResponseController
+}
+
+func newStreamWriter(c *gin.Context) *streamWriter {
+	return &streamWriter{c: c, rc: http.NewResponseController(c.Writer)}
+}
+
+func (w *streamWriter) Write(p []byte) (int, error) {
+	return w.c.Writer.Write(p)
+}
+
+// Flush は書き込んだ内容をクライアントに送り、次の書き込みの期限を延ばす
+func (w *streamWriter) Flush() {
+	w.c.Writer.Flush()
+	// 期限の変更に対応していないWriter（テストなど）では延長しない
+	_ = w.rc.SetWriteDeadline(time.Now().Add(streamWriterTimeout))
+}
+
+// copyStream はrの内容をチャンクごとに送り出し、その都度書き込みの期限を延ばす
+// 大きなファイルでもWriteTimeoutで途中で切れないようにする
+func copyStream(c *gin.Context, r io.Reader) (int64, error) {
+	w := newStreamWriter(c)
+	buf := make([]byte, streamChunkSize)
+	var written int64
+	for {
+		n, err := io.ReadFull(r, buf)
+		if n > 0 {
+			m, werr := w.Write(buf[:n])
+			written += int64(m)
+			if werr != nil {
+				return written, werr
+			}
+			w.Flush()
+		}
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return written, nil
+		}
+		if err != nil {
+			return written, err
+		}
+	}
+}
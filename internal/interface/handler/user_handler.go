@@ -1,7 +1,9 @@
 package handler
 
 import (
-	"log"
+	"errors"
+	"io"
+	"log/slog"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -9,82 +11,196 @@ import (
 	"seat-management-backend/internal/domain/entity"
 	"seat-management-backend/internal/middleware"
 	"seat-management-backend/internal/usecase"
+	"seat-management-backend/pkg/i18n"
 )
 
 type UserHandler struct {
-	userUsecase usecase.UserUsecase
+	userUsecase    usecase.UserUsecase
+	avatarUsecase  usecase.AvatarUsecase
+	maxUploadBytes int64
 }
 
 type UpdateProfileRequest struct {
-	Name                  *string `json:"name,omitempty"`
-	AvatarURL             *string `json:"avatar_url,omitempty"`
-	DefaultPrivacySetting *string `json:"default_privacy_setting,omitempty"`
+	Name                  *string `json:"name,omitempty" binding:"omitempty,min=1,max=100"`
+	AvatarURL             *string `json:"avatar_url,omitempty" binding:"omitempty,http_url,max=500"`
+	DefaultPrivacySetting *string `json:"default_privacy_setting,omitempty" binding:"omitempty,privacy_setting"`
+	// 空文字を指定するとAccept-Languageに従う
+	Locale *string `json:"locale,omitempty" binding:"omitempty,locale"`
 }
 
-func NewUserHandler(uu usecase.UserUsecase) *UserHandler {
+// NewUserHandler はUserHandlerを作成する
+// maxUploadBytesはアップロードできる画像ファイルの最大サイズ
+func NewUserHandler(uu usecase.UserUsecase, au usecase.AvatarUsecase, maxUploadBytes int64) *UserHandler {
 	return &UserHandler{
-		userUsecase: uu,
+		userUsecase:    uu,
+		avatarUsecase:  au,
+		maxUploadBytes: maxUploadBytes,
 	}
 }
 
 // ユーザー情報を取得
 func (h *UserHandler) GetMe(c *gin.Context) {
-	clerkUserID, err := middleware.GetClerkUserID(c)
-	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "認証されていません"})
-		return
-	}
-
-	user, err := h.userUsecase.GetByClerkUserID(c.Request.Context(), clerkUserID)
-	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "ユーザーが見つかりません"})
+	user, ok := h.currentUser(c)
+	if !ok {
 		return
 	}
 
 	// 最終ログイン時刻を更新
 	if err := h.userUsecase.UpdateLastLogin(c.Request.Context(), user.ID); err != nil {
-		log.Println("Failed to update last login for user :", user.ID, err)
+		slog.WarnContext(c.Request.Context(), "failed to update last login", "user_id", user.ID, "error", err)
 	}
 
+	setETag(c, user.Version)
 	c.JSON(http.StatusOK, user)
 }
 
-// ユーザー情報の更新
+// ユーザー情報の部分更新
+// 指定された項目だけを更新し、If-Matchがあればバージョンが一致する場合のみ更新する
 func (h *UserHandler) UpdateProfile(c *gin.Context) {
-	clerkUserID, err := middleware.GetClerkUserID(c)
+	var req UpdateProfileRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	user, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+
+	if !ifMatch(c, user.Version) {
+		_ = c.Error(entity.ErrVersionConflict)
+		return
+	}
+
+	fields := req.applyTo(user)
+	if err := h.userUsecase.Update(c.Request.Context(), user, fields...); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	setETag(c, user.Version)
+	c.JSON(http.StatusOK, user)
+}
+
+// アバター画像のアップロード
+// multipart/form-dataのfileに画像（JPEG・PNG・GIF・WebP）を指定する
+func (h *UserHandler) UploadAvatar(c *gin.Context) {
+	// フォームの他の項目やmultipartの区切りの分だけ余裕を持たせる
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.maxUploadBytes+64<<10)
+
+	data, err := h.readAvatarFile(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		_ = c.Error(err)
 		return
 	}
 
-	var req UpdateProfileRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	user, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+
+	if !ifMatch(c, user.Version) {
+		_ = c.Error(entity.ErrVersionConflict)
 		return
 	}
 
-	user, err := h.userUsecase.GetByClerkUserID(c.Request.Context(), clerkUserID)
-	if err := c.ShouldBindJSON(&user); err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	result, err := h.avatarUsecase.Upload(c.Request.Context(), user, data)
+	if err != nil {
+		_ = c.Error(err)
 		return
 	}
 
-	// 変更フィールドのみ適用
+	setETag(c, result.User.Version)
+	c.JSON(http.StatusOK, result)
+}
+
+// readAvatarFile はアップロードされたファイルを読み込む
+func (h *UserHandler) readAvatarFile(c *gin.Context) ([]byte, error) {
+	fh, err := c.FormFile("file")
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			return nil, entity.ErrFileTooLarge
+		}
+		return nil, middleware.ErrInvalidRequest
+	}
+	if fh.Size > h.maxUploadBytes {
+		return nil, entity.ErrFileTooLarge
+	}
+
+	f, err := fh.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(io.LimitReader(f, h.maxUploadBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > h.maxUploadBytes {
+		return nil, entity.ErrFileTooLarge
+	}
+	return data, nil
+}
+
+// applyTo は指定された項目をユーザーに反映し、変更したカラム名を返す
+func (req *UpdateProfileRequest) applyTo(user *entity.User) []string {
+	var fields []string
 	if req.Name != nil {
 		user.Name = *req.Name
+		fields = append(fields, entity.UserColumnName)
 	}
 	if req.AvatarURL != nil {
 		user.AvatarURL = req.AvatarURL
+		fields = append(fields, entity.UserColumnAvatarURL)
 	}
 	if req.DefaultPrivacySetting != nil {
 		user.DefaultPrivacySetting = entity.PrivacySetting(*req.DefaultPrivacySetting)
+		fields = append(fields, entity.UserColumnDefaultPrivacySetting)
+	}
+	if req.Locale != nil {
+		if *req.Locale == "" {
+			user.Locale = nil
+		} else {
+			user.Locale = req.Locale
+		}
+		fields = append(fields, entity.UserColumnLocale)
+	}
+	return fields
+}
+
+func (h *UserHandler) currentUser(c *gin.Context) (*entity.User, bool) {
+	return currentUser(c, h.userUsecase)
+}
+
+// currentUser は認証済みのユーザーを取得する
+// 取得できない場合はエラーを積んでfalseを返す
+func currentUser(c *gin.Context, uu usecase.UserUsecase) (*entity.User, bool) {
+	clerkUserID, err := middleware.GetClerkUserID(c)
+	if err != nil {
+		_ = c.Error(middleware.ErrUnauthenticated)
+		return nil, false
 	}
 
-	if err := h.userUsecase.Update(c.Request.Context(), user); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	user, err := uu.GetByClerkUserID(c.Request.Context(), clerkUserID)
+	if err != nil {
+		_ = c.Error(err)
+		return nil, false
 	}
 
-	c.JSON(http.StatusOK, user)
+	// ユーザーが言語を設定している場合はAccept-Languageより優先する
+	if user.Locale != nil {
+		if locale, ok := i18n.ParseLocale(*user.Locale); ok {
+			middleware.SetLocale(c, locale)
+		}
+	}
+
+	if user.IsSuspended() {
+		_ = c.Error(entity.ErrUserSuspended)
+		return nil, false
+	}
+	return user, true
 }
 
 // RegisterRoutes はユーザールートを登録
@@ -93,6 +209,9 @@ func (h *UserHandler) RegisterRoutes(r *gin.Engine) {
 	users.Use(middleware.ClerkAuthMiddleware())
 	{
 		users.GET("/me", h.GetMe)
+		users.PATCH("/me", h.UpdateProfile)
+		// 後方互換のため残している（動作はPATCHと同じ部分更新）
 		users.PUT("/me", h.UpdateProfile)
+		users.POST("/me/avatar", h.UploadAvatar)
 	}
 }
@@ -1,29 +1,50 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"net/http"
-	"os"
-	"strings"
 
 	"github.com/gin-gonic/gin"
 	svix "github.com/svix/svix-webhooks/go"
 
 	"seat-management-backend/internal/domain/entity"
+	"seat-management-backend/internal/metrics"
+	"seat-management-backend/internal/middleware"
 	"seat-management-backend/internal/usecase"
+	"seat-management-backend/pkg/audit"
+	"seat-management-backend/pkg/i18n"
 )
 
+// webhookUpdateMaxAttempts はバージョン競合時にuser.updatedの反映を試みる回数
+const webhookUpdateMaxAttempts = 3
+
 type WebhookHandler struct {
-	userUsecase usecase.UserUsecase
+	userUsecase   usecase.UserUsecase
+	auditUsecase  usecase.AuditUsecase
+	avatarUsecase usecase.AvatarUsecase
+	webhook       *svix.Webhook
+	metrics       *metrics.Metrics
 }
 
-func NewWebhookHandler(uu usecase.UserUsecase) *WebhookHandler {
-	return &WebhookHandler{
-		userUsecase: uu,
+// NewWebhookHandler は署名シークレットで検証器を初期化したWebhookHandlerを返す
+func NewWebhookHandler(uu usecase.UserUsecase, au usecase.AuditUsecase, avu usecase.AvatarUsecase, webhookSecret string, m *metrics.Metrics) (*WebhookHandler, error) {
+	wh, err := svix.NewWebhook(webhookSecret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize webhook: %w", err)
 	}
+
+	return &WebhookHandler{
+		userUsecase:   uu,
+		auditUsecase:  au,
+		avatarUsecase: avu,
+		webhook:       wh,
+		metrics:       m,
+	}, nil
 }
 
 // WebhookEvent はClerkからのWebhookイベントの構造
@@ -48,6 +69,17 @@ type ClerkEmailAddress struct {
 	EmailAddress string `json:"email_address"`
 }
 
+// ClerkOrganizationMembershipData は組織メンバーシップのWebhookデータの構造
+type ClerkOrganizationMembershipData struct {
+	Role         string `json:"role"`
+	Organization struct {
+		ID string `json:"id"`
+	} `json:"organization"`
+	PublicUserData struct {
+		UserID string `json:"user_id"`
+	} `json:"public_user_data"`
+}
+
 type ClerkExternalAccount struct {
 	Provider     string `json:"provider"`
 	EmailAddress string `json:"email_address"`
@@ -58,118 +90,104 @@ type ClerkExternalAccount struct {
 
 // HandleClerkWebhook はClerkからのWebhookを処理
 func (h *WebhookHandler) HandleClerkWebhook(c *gin.Context) {
+	ctx := c.Request.Context()
+	locale := i18n.FromContext(ctx)
+
 	payload, err := io.ReadAll(c.Request.Body)
 	if err != nil {
-		log.Printf("[Webhook] Failed to read body: %v", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "リクエストボディの読み取りに失敗しました"})
+		slog.ErrorContext(ctx, "webhook: failed to read body", "error", err)
+		_ = c.Error(middleware.ErrInvalidRequest)
 		return
 	}
 
-	if os.Getenv("GIN_MODE") == "debug" {
-		log.Printf("[Webhook] Received payload: %s", string(payload))
-	}
+	// ペイロードには個人情報が含まれるため、本文はログに出さない
+	slog.DebugContext(ctx, "webhook: received", "svix_id", c.GetHeader("svix-id"), "size", len(payload))
 
 	headers := http.Header{}
 	headers.Set("svix-id", c.GetHeader("svix-id"))
 	headers.Set("svix-timestamp", c.GetHeader("svix-timestamp"))
 	headers.Set("svix-signature", c.GetHeader("svix-signature"))
 
-	webhookSecret := os.Getenv("CLERK_WEBHOOK_SECRET")
-	if webhookSecret == "" {
-		log.Printf("[Webhook] CLERK_WEBHOOK_SECRET is not set")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Webhook設定エラー"})
-		return
-	}
-
-	wh, err := svix.NewWebhook(webhookSecret)
-	if err != nil {
-		log.Printf("[Webhook] Failed to initialize webhook: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Webhook初期化に失敗しました"})
-		return
-	}
-
 	var evt WebhookEvent
-	err = wh.Verify(payload, headers)
+	err = h.webhook.Verify(payload, headers)
 	if err != nil {
-		log.Printf("[Webhook] Signature verification failed: %v", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Webhook署名の検証に失敗しました"})
+		slog.WarnContext(ctx, "webhook: signature verification failed", "error", err)
+		h.metrics.WebhookEvent("unknown", "invalid_signature")
+		_ = c.Error(middleware.ErrInvalidWebhookSignature)
 		return
 	}
 
 	if err := json.Unmarshal(payload, &evt); err != nil {
-		log.Printf("[Webhook] Failed to parse event: %v", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "イベントのパースに失敗しました"})
+		slog.WarnContext(ctx, "webhook: failed to parse event", "error", err)
+		h.metrics.WebhookEvent("unknown", "parse_error")
+		_ = c.Error(middleware.ErrInvalidRequest)
 		return
 	}
 
-	log.Printf("[Webhook] Event type: %s", evt.Type)
+	slog.InfoContext(ctx, "webhook: event received", "event_type", evt.Type, "svix_id", c.GetHeader("svix-id"))
 
 	switch evt.Type {
 	case "user.created":
 		if err := h.handleUserCreated(c, evt.Data); err != nil {
-			log.Printf("[Webhook] user.created handler error: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"message": "イベントを受信しましたが、処理中にエラーが発生しました",
-				"error":   err.Error(),
-			})
+			slog.ErrorContext(ctx, "webhook: handler failed", "event_type", evt.Type, "error", err)
+			h.metrics.WebhookEvent(evt.Type, "error")
+			_ = c.Error(err)
 			return
 		}
 	case "user.updated":
 		if err := h.handleUserUpdated(c, evt.Data); err != nil {
-			log.Printf("[Webhook] user.updated handler error: %v", err)
-			c.JSON(http.StatusOK, gin.H{
-				"message": "イベントを受信しましたが、処理中にエラーが発生しました",
-				"error":   err.Error(),
-			})
+			slog.ErrorContext(ctx, "webhook: handler failed", "event_type", evt.Type, "error", err)
+			h.metrics.WebhookEvent(evt.Type, "error")
+			c.JSON(http.StatusOK, gin.H{"message": i18n.T(locale, "webhook.failed")})
 			return
 		}
 	case "user.deleted":
 		if err := h.handleUserDeleted(c, evt.Data); err != nil {
-			log.Printf("[Webhook] user.deleted handler error: %v", err)
-			c.JSON(http.StatusOK, gin.H{
-				"message": "イベントを受信しましたが、処理中にエラーが発生しました",
-				"error":   err.Error(),
-			})
+			slog.ErrorContext(ctx, "webhook: handler failed", "event_type", evt.Type, "error", err)
+			h.metrics.WebhookEvent(evt.Type, "error")
+			c.JSON(http.StatusOK, gin.H{"message": i18n.T(locale, "webhook.failed")})
+			return
+		}
+	case "organizationMembership.created", "organizationMembership.updated", "organizationMembership.deleted":
+		if err := h.handleMembershipChanged(c, evt.Type, evt.Data); err != nil {
+			slog.ErrorContext(ctx, "webhook: handler failed", "event_type", evt.Type, "error", err)
+			h.metrics.WebhookEvent(evt.Type, "error")
+			c.JSON(http.StatusOK, gin.H{"message": i18n.T(locale, "webhook.failed")})
 			return
 		}
 	default:
-		log.Printf("[Webhook] Unhandled event type: %s", evt.Type)
-		c.JSON(http.StatusOK, gin.H{"message": "処理対象外のイベントタイプです"})
+		slog.InfoContext(ctx, "webhook: unhandled event type", "event_type", evt.Type)
+		h.metrics.WebhookEvent(evt.Type, "ignored")
+		c.JSON(http.StatusOK, gin.H{"message": i18n.T(locale, "webhook.ignored")})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Webhookの処理が完了しました"})
+	h.metrics.WebhookEvent(evt.Type, "success")
+	c.JSON(http.StatusOK, gin.H{"message": i18n.T(locale, "webhook.processed")})
 }
 
 // 認証プロバイダーを判定する
-func determineAuthProvider(clerkUser ClerkUserData) entity.AuthProvider {
-	// デバッグログを追加
-	log.Printf("[Webhook] Determining auth provider...")
-	log.Printf("[Webhook] External accounts count: %d", len(clerkUser.ExternalAccounts))
-	log.Printf("[Webhook] Password enabled: %v", clerkUser.PasswordEnabled)
+func determineAuthProvider(ctx context.Context, clerkUser ClerkUserData) entity.AuthProvider {
+	slog.DebugContext(ctx, "webhook: determining auth provider",
+		"external_accounts", len(clerkUser.ExternalAccounts),
+		"password_enabled", clerkUser.PasswordEnabled)
 
 	// 外部アカウント（Google）が存在する場合
 	if len(clerkUser.ExternalAccounts) > 0 {
 		provider := clerkUser.ExternalAccounts[0].Provider
-		log.Printf("[Webhook] External account provider: %s", provider)
-
-		switch provider {
-		case "oauth_google":
-			log.Printf("[Webhook] Detected Google OAuth")
-			return entity.AuthProviderGoogle
-		default:
-			log.Printf("[Webhook] Unknown OAuth provider: %s", provider)
-			return entity.AuthProviderUnknown
+		authProvider := entity.AuthProviderFromOAuth(provider)
+		if authProvider == entity.AuthProviderUnknown {
+			slog.WarnContext(ctx, "webhook: unknown OAuth provider", "provider", provider)
 		}
+		return authProvider
 	}
 
 	// パスワード認証が有効な場合
 	if clerkUser.PasswordEnabled {
-		log.Printf("[Webhook] Detected password-based authentication")
 		return entity.AuthProviderEmail
 	}
 
-	log.Printf("[Webhook] Could not determine auth provider, returning unknown")
+	slog.DebugContext(ctx, "webhook: could not determine auth provider")
 	return entity.AuthProviderUnknown
 }
 
@@ -179,10 +197,11 @@ func (h *WebhookHandler) handleUserCreated(c *gin.Context, data json.RawMessage)
 		return fmt.Errorf("ユーザーデータのパース失敗: %w", err)
 	}
 
-	log.Printf("[Webhook] Processing user.created for Clerk ID: %s", clerkUser.ID)
+	ctx := c.Request.Context()
+	slog.InfoContext(ctx, "webhook: processing user.created", "clerk_user_id", clerkUser.ID)
 
 	if len(clerkUser.EmailAddresses) == 0 {
-		log.Printf("[Webhook] No email addresses found for user %s (test event?)", clerkUser.ID)
+		slog.WarnContext(ctx, "webhook: no email addresses found (test event?)", "clerk_user_id", clerkUser.ID)
 		return fmt.Errorf("メールアドレスが見つかりません（テストイベントの可能性があります）")
 	}
 
@@ -191,26 +210,16 @@ func (h *WebhookHandler) handleUserCreated(c *gin.Context, data json.RawMessage)
 		return fmt.Errorf("メールアドレスが空です")
 	}
 
-	name := ""
-	if clerkUser.FirstName != nil && clerkUser.LastName != nil {
-		name = fmt.Sprintf("%s %s", *clerkUser.FirstName, *clerkUser.LastName)
-	} else if clerkUser.FirstName != nil {
-		name = *clerkUser.FirstName
-	} else if clerkUser.LastName != nil {
-		name = *clerkUser.LastName
-	} else {
-		name = strings.Split(email, "@")[0]
-	}
+	name := entity.BuildDisplayName(clerkUser.FirstName, clerkUser.LastName, email)
 
-	existingUser, err := h.userUsecase.GetByClerkUserID(c.Request.Context(), clerkUser.ID)
+	existingUser, err := h.userUsecase.GetByClerkUserID(ctx, clerkUser.ID)
 	if err == nil && existingUser != nil {
-		log.Printf("[Webhook] User already exists: %s", clerkUser.ID)
+		slog.InfoContext(ctx, "webhook: user already exists", "clerk_user_id", clerkUser.ID)
 		return nil
 	}
 
 	// ⭐ 認証プロバイダーを判定
-	authProvider := determineAuthProvider(clerkUser)
-	log.Printf("[Webhook] Determined auth provider: %s", authProvider)
+	authProvider := determineAuthProvider(ctx, clerkUser)
 
 	user := &entity.User{
 		ClerkUserID:           clerkUser.ID,
@@ -221,11 +230,12 @@ func (h *WebhookHandler) handleUserCreated(c *gin.Context, data json.RawMessage)
 		PrimaryAuthProvider:   authProvider,
 	}
 
-	if err := h.userUsecase.Create(c.Request.Context(), user); err != nil {
+	if err := h.userUsecase.Create(ctx, user); err != nil {
 		return fmt.Errorf("ユーザーの作成失敗: %w", err)
 	}
 
-	log.Printf("[Webhook] User created successfully: %s (%s) with provider: %s", clerkUser.ID, email, authProvider)
+	slog.InfoContext(ctx, "webhook: user created",
+		"clerk_user_id", clerkUser.ID, "user_id", user.ID, "email", email, "auth_provider", authProvider)
 	return nil
 }
 
@@ -235,37 +245,48 @@ func (h *WebhookHandler) handleUserUpdated(c *gin.Context, data json.RawMessage)
 		return fmt.Errorf("ユーザーデータのパース失敗: %w", err)
 	}
 
-	log.Printf("[Webhook] Processing user.updated for Clerk ID: %s", clerkUser.ID)
+	ctx := c.Request.Context()
+	slog.InfoContext(ctx, "webhook: processing user.updated", "clerk_user_id", clerkUser.ID)
 
-	user, err := h.userUsecase.GetByClerkUserID(c.Request.Context(), clerkUser.ID)
-	if err != nil {
-		return fmt.Errorf("ユーザーが見つかりません: %w", err)
-	}
-
-	if len(clerkUser.EmailAddresses) > 0 {
-		user.Email = clerkUser.EmailAddresses[0].EmailAddress
-	}
+	authProvider := determineAuthProvider(ctx, clerkUser)
 
-	name := ""
-	if clerkUser.FirstName != nil && clerkUser.LastName != nil {
-		name = fmt.Sprintf("%s %s", *clerkUser.FirstName, *clerkUser.LastName)
-	} else if clerkUser.FirstName != nil {
-		name = *clerkUser.FirstName
-	} else if clerkUser.LastName != nil {
-		name = *clerkUser.LastName
-	}
-	if name != "" {
-		user.Name = name
-	}
+	// Clerkが管理する項目だけを上書きする
+	// ユーザー自身の更新と競合した場合は最新の状態を読み直して再試行する
+	var user *entity.User
+	for attempt := 1; ; attempt++ {
+		var err error
+		user, err = h.userUsecase.GetByClerkUserID(ctx, clerkUser.ID)
+		if err != nil {
+			return fmt.Errorf("ユーザーが見つかりません: %w", err)
+		}
 
-	user.AvatarURL = clerkUser.ImageURL
-	user.PrimaryAuthProvider = determineAuthProvider(clerkUser)
+		fields := []string{entity.UserColumnPrimaryAuthProvider}
+		// アップロードされたアバターはClerkのプロフィール画像で上書きしない
+		if !h.avatarUsecase.IsUploaded(user) {
+			user.AvatarURL = clerkUser.ImageURL
+			fields = append(fields, entity.UserColumnAvatarURL)
+		}
+		if len(clerkUser.EmailAddresses) > 0 {
+			user.Email = clerkUser.EmailAddresses[0].EmailAddress
+			fields = append(fields, entity.UserColumnEmail)
+		}
+		if name := entity.BuildDisplayName(clerkUser.FirstName, clerkUser.LastName, ""); name != "" {
+			user.Name = name
+			fields = append(fields, entity.UserColumnName)
+		}
+		user.PrimaryAuthProvider = authProvider
 
-	if err := h.userUsecase.Update(c.Request.Context(), user); err != nil {
-		return fmt.Errorf("ユーザーの更新失敗: %w", err)
+		err = h.userUsecase.Update(ctx, user, fields...)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, entity.ErrVersionConflict) || attempt >= webhookUpdateMaxAttempts {
+			return fmt.Errorf("ユーザーの更新失敗: %w", err)
+		}
+		slog.InfoContext(ctx, "webhook: version conflict, retrying", "clerk_user_id", clerkUser.ID, "attempt", attempt)
 	}
 
-	log.Printf("[Webhook] User updated successfully: %s", clerkUser.ID)
+	slog.InfoContext(ctx, "webhook: user updated", "clerk_user_id", clerkUser.ID, "user_id", user.ID)
 	return nil
 }
 
@@ -275,22 +296,51 @@ func (h *WebhookHandler) handleUserDeleted(c *gin.Context, data json.RawMessage)
 		return fmt.Errorf("ユーザーデータのパース失敗: %w", err)
 	}
 
-	log.Printf("[Webhook] Processing user.deleted for Clerk ID: %s", clerkUser.ID)
+	ctx := c.Request.Context()
+	slog.InfoContext(ctx, "webhook: processing user.deleted", "clerk_user_id", clerkUser.ID)
 
-	user, err := h.userUsecase.GetByClerkUserID(c.Request.Context(), clerkUser.ID)
+	user, err := h.userUsecase.GetByClerkUserID(ctx, clerkUser.ID)
 	if err != nil {
 		return fmt.Errorf("ユーザーが見つかりません: %w", err)
 	}
 
-	if err := h.userUsecase.Delete(c.Request.Context(), user.ID); err != nil {
+	if err := h.userUsecase.Delete(ctx, user.ID); err != nil {
 		return fmt.Errorf("ユーザーの削除失敗: %w", err)
 	}
 
-	log.Printf("[Webhook] User deleted successfully: %s", clerkUser.ID)
+	slog.InfoContext(ctx, "webhook: user deleted", "clerk_user_id", clerkUser.ID, "user_id", user.ID)
+	return nil
+}
+
+// handleMembershipChanged は組織のロールの付与・変更・削除を監査ログに記録する
+// ロールはClerkで管理しているため、このサービスのデータは変更しない
+func (h *WebhookHandler) handleMembershipChanged(c *gin.Context, eventType string, data json.RawMessage) error {
+	var membership ClerkOrganizationMembershipData
+	if err := json.Unmarshal(data, &membership); err != nil {
+		return fmt.Errorf("メンバーシップデータのパース失敗: %w", err)
+	}
+
+	ctx := c.Request.Context()
+	clerkUserID := membership.PublicUserData.UserID
+	slog.InfoContext(ctx, "webhook: processing "+eventType, "clerk_user_id", clerkUserID, "role", membership.Role)
+
+	user, err := h.userUsecase.GetByClerkUserID(ctx, clerkUserID)
+	if err != nil {
+		return fmt.Errorf("ユーザーが見つかりません: %w", err)
+	}
+
+	// Clerkは変更前のロールを送らないため、付与・変更では変更後の値だけを記録する
+	role := audit.Change{After: membership.Role}
+	if eventType == "organizationMembership.deleted" {
+		role = audit.Change{Before: membership.Role}
+	}
+	// 組織ごとのロールとして "organizations.<組織ID>.role" の項目名で記録する
+	field := "organizations." + membership.Organization.ID + ".role"
+	h.auditUsecase.Record(ctx, entity.AuditRoleChanged, entity.AuditTargetUser, user.ID, audit.Changes{field: role})
 	return nil
 }
 
 // RegisterRoutes はWebhookルートを登録
 func (h *WebhookHandler) RegisterRoutes(r *gin.Engine) {
-	r.POST("/api/webhooks/clerk", h.HandleClerkWebhook)
+	r.POST("/api/webhooks/clerk", middleware.WebhookAuditActor("clerk"), h.HandleClerkWebhook)
 }
@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	svix "github.com/svix/svix-webhooks/go"
+
+	"seat-management-backend/internal/domain/entity"
+	"seat-management-backend/internal/usecase"
+	"seat-management-backend/pkg/storage"
+)
+
+var testWebhookSecret = "whsec_" + base64.StdEncoding.EncodeToString([]byte("webhook-test-secret"))
+
+// fakeUserUsecase はメモリ上のユーザーを扱うUserUsecase
+// テストで使わないメソッドは埋め込んだnilのインターフェースに委ねる（呼ぶとpanicする）
+type fakeUserUsecase struct {
+	usecase.UserUsecase
+	users map[string]*entity.User
+}
+
+func (u *fakeUserUsecase) GetByClerkUserID(_ context.Context, clerkUserID string) (*entity.User, error) {
+	for _, user := range u.users {
+		if user.ClerkUserID == clerkUserID {
+			found := *user
+			return &found, nil
+		}
+	}
+	return nil, entity.ErrUserNotFound
+}
+
+// Update はfieldsで指定した項目だけを保存する
+func (u *fakeUserUsecase) Update(_ context.Context, user *entity.User, fields ...string) error {
+	stored := u.users[user.ID]
+	for _, field := range fields {
+		switch field {
+		case entity.UserColumnEmail:
+			stored.Email = user.Email
+		case entity.UserColumnName:
+			stored.Name = user.Name
+		case entity.UserColumnAvatarURL:
+			stored.AvatarURL = user.AvatarURL
+		case entity.UserColumnPrimaryAuthProvider:
+			stored.PrimaryAuthProvider = user.PrimaryAuthProvider
+		}
+	}
+	return nil
+}
+
+// postWebhook は署名したイベントをハンドラーに送る
+func postWebhook(t *testing.T, r *gin.Engine, eventType string, data any) *httptest.ResponseRecorder {
+	t.Helper()
+	raw, err := json.Marshal(data)
+	if err != nil {
+		t.Fatalf("marshal data: %v", err)
+	}
+	payload, err := json.Marshal(WebhookEvent{Type: eventType, Object: "event", Data: raw})
+	if err != nil {
+		t.Fatalf("marshal event: %v", err)
+	}
+
+	wh, err := svix.NewWebhook(testWebhookSecret)
+	if err != nil {
+		t.Fatalf("NewWebhook: %v", err)
+	}
+	now := time.Now()
+	signature, err := wh.Sign("msg_test", now, payload)
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/webhooks/clerk", bytes.NewReader(payload))
+	req.Header.Set("svix-id", "msg_test")
+	req.Header.Set("svix-timestamp", strconv.FormatInt(now.Unix(), 10))
+	req.Header.Set("svix-signature", signature)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestWebhookUserUpdatedKeepsUploadedAvatar(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	store, err := storage.NewLocalStore(t.TempDir(), "http://localhost/uploads")
+	if err != nil {
+		t.Fatalf("NewLocalStore: %v", err)
+	}
+
+	const userID = "01J00000000000000000000001"
+	uploadedURL := store.URL(path.Join(usecase.AvatarKeyPrefix, userID, "01J00000000000000000000009", "256.jpg"))
+	users := &fakeUserUsecase{users: map[string]*entity.User{
+		userID: {ID: userID, ClerkUserID: "user_test", Email: "old@example.com", Name: "Old", AvatarURL: &uploadedURL},
+	}}
+	h, err := NewWebhookHandler(users, nil, usecase.NewAvatarUsecase(nil, nil, store), testWebhookSecret, nil)
+	if err != nil {
+		t.Fatalf("NewWebhookHandler: %v", err)
+	}
+	r := gin.New()
+	h.RegisterRoutes(r)
+
+	first, last := "New", "Name"
+	clerkURL := "https://img.clerk.com/new"
+	data := ClerkUserData{
+		ID:             "user_test",
+		EmailAddresses: []ClerkEmailAddress{{EmailAddress: "new@example.com"}},
+		FirstName:      &first,
+		LastName:       &last,
+		ImageURL:       &clerkURL,
+	}
+
+	rec := postWebhook(t, r, "user.updated", data)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body)
+	}
+	user := users.users[userID]
+	if user.AvatarURL == nil || *user.AvatarURL != uploadedURL {
+		t.Errorf("avatar = %v, want the uploaded avatar %s kept", user.AvatarURL, uploadedURL)
+	}
+	if user.Email != "new@example.com" {
+		t.Errorf("email = %q, want the other Clerk fields still applied", user.Email)
+	}
+
+	// Clerkの画像のままなら新しい画像に置き換わる
+	oldClerkURL := "https://img.clerk.com/old"
+	user.AvatarURL = &oldClerkURL
+	rec = postWebhook(t, r, "user.updated", data)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body)
+	}
+	if user.AvatarURL == nil || *user.AvatarURL != clerkURL {
+		t.Errorf("avatar = %v, want the Clerk image %s", user.AvatarURL, clerkURL)
+	}
+}
@@ -0,0 +1,131 @@
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+
+	"seat-management-backend/internal/domain/entity"
+	"seat-management-backend/pkg/i18n"
+)
+
+// FieldError は検証に失敗した入力項目
+type FieldError struct {
+	// JSONまたはクエリパラメータ上の項目名（ネストしている場合はドット区切り）
+	Field string `json:"field"`
+	// 失敗した検証ルール（required, max, privacy_setting など）
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// customValidators はドメインの列挙型などを検証する独自ルール
+// DTOではbindingタグで `binding:"omitempty,privacy_setting"` のように指定する
+var customValidators = map[string]validator.Func{
+	"privacy_setting": func(fl validator.FieldLevel) bool {
+		return entity.PrivacySetting(fl.Field().String()).IsValid()
+	},
+	"auth_provider": func(fl validator.FieldLevel) bool {
+		return entity.AuthProvider(fl.Field().String()).IsValid()
+	},
+	// 空文字は「言語設定を解除する」の意味で許可する
+	"locale": func(fl validator.FieldLevel) bool {
+		v := fl.Field().String()
+		if v == "" {
+			return true
+		}
+		_, ok := i18n.ParseLocale(v)
+		return ok
+	},
+}
+
+// Register はGinのバインディングで使われるバリデーターに独自ルールを登録する
+// ShouldBind系のメソッドでバインドするすべてのDTOに適用される
+func Register() error {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return errors.New("unexpected validator engine")
+	}
+
+	// エラーの項目名にGoのフィールド名ではなくJSON・クエリパラメータ上の名前を使う
+	v.RegisterTagNameFunc(fieldName)
+
+	for tag, fn := range customValidators {
+		if err := v.RegisterValidation(tag, fn); err != nil {
+			return fmt.Errorf("failed to register validator %s: %w", tag, err)
+		}
+	}
+	return nil
+}
+
+// FieldErrors は検証エラーを項目ごとのエラーに変換する
+// 検証エラーでない場合（JSONの構文エラーなど）はfalseを返す
+func FieldErrors(err error, locale i18n.Locale) ([]FieldError, bool) {
+	var verrs validator.ValidationErrors
+	if !errors.As(err, &verrs) {
+		return nil, false
+	}
+
+	fields := make([]FieldError, 0, len(verrs))
+	for _, fe := range verrs {
+		fields = append(fields, FieldError{
+			Field:   fieldPath(fe),
+			Rule:    fe.Tag(),
+			Message: message(fe, locale),
+		})
+	}
+	return fields, true
+}
+
+func fieldName(fld reflect.StructField) string {
+	for _, tag := range []string{"json", "form", "uri"} {
+		name, _, _ := strings.Cut(fld.Tag.Get(tag), ",")
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return ""
+}
+
+// fieldPath は先頭の構造体名と埋め込み構造体の名前を除いた項目のパスを返す
+// タグのない埋め込み構造体（PageQueryなど）はGoの型名のまま残るため、大文字で始まる要素を除く
+func fieldPath(fe validator.FieldError) string {
+	segments := strings.Split(fe.Namespace(), ".")
+	path := make([]string, 0, len(segments))
+	for _, seg := range segments[1:] {
+		if seg != "" && unicode.IsUpper(rune(seg[0])) {
+			continue
+		}
+		path = append(path, seg)
+	}
+	if len(path) == 0 {
+		return fe.Field()
+	}
+	return strings.Join(path, ".")
+}
+
+func message(fe validator.FieldError, locale i18n.Locale) string {
+	key := "validation." + fe.Tag()
+	// 文字列の長さの制約は数値の範囲と文言を分ける
+	if fe.Kind() == reflect.String && (fe.Tag() == "min" || fe.Tag() == "max") {
+		key += "_length"
+	}
+	if i18n.T(locale, key) == key {
+		key = "validation.invalid"
+	}
+	msg, err := i18n.Render(locale, key, map[string]string{
+		"Field": fieldPath(fe),
+		"Param": fe.Param(),
+	})
+	if err != nil {
+		return i18n.T(locale, "validation.invalid")
+	}
+	return msg
+}
@@ -0,0 +1,109 @@
+package validation
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gin-gonic/gin/binding"
+
+	"seat-management-backend/pkg/i18n"
+)
+
+type profile struct {
+	Privacy string `json:"privacy" binding:"omitempty,privacy_setting"`
+}
+
+type testRequest struct {
+	PageQuery
+	Name    string   `json:"name" binding:"required,max=5"`
+	Email   string   `json:"email" binding:"omitempty,email"`
+	Locale  string   `json:"locale" binding:"locale"`
+	Profile *profile `json:"profile"`
+	Ignored string   `json:"-" binding:"required"`
+}
+
+// PageQuery はタグのない埋め込み構造体として項目名から除かれることを確認するため公開する
+type PageQuery struct {
+	Cursor string `form:"cursor" binding:"omitempty,max=3"`
+}
+
+func validate(t *testing.T, req any) error {
+	t.Helper()
+	if err := Register(); err != nil {
+		t.Fatalf("Register() returned error: %v", err)
+	}
+	return binding.Validator.ValidateStruct(req)
+}
+
+func TestFieldErrors(t *testing.T) {
+	req := &testRequest{
+		PageQuery: PageQuery{Cursor: "long"},
+		Name:      "toolong",
+		Email:     "not-an-email",
+		Locale:    "fr",
+		Profile:   &profile{Privacy: "everyone"},
+		Ignored:   "set",
+	}
+
+	fields, ok := FieldErrors(validate(t, req), i18n.English)
+	if !ok {
+		t.Fatal("FieldErrors should recognise validation errors")
+	}
+
+	got := make(map[string]FieldError, len(fields))
+	for _, f := range fields {
+		got[f.Field] = f
+	}
+	want := map[string]struct{ rule, message string }{
+		"cursor":          {"max", "cursor must be at most 3 characters"},
+		"name":            {"max", "name must be at most 5 characters"},
+		"email":           {"email", "email must be a valid email address"},
+		"locale":          {"locale", "locale must be ja or en"},
+		"profile.privacy": {"privacy_setting", "profile.privacy must be one of public, friends, private"},
+	}
+	if len(got) != len(want) {
+		t.Errorf("got %d field errors, want %d: %+v", len(got), len(want), fields)
+	}
+	for field, w := range want {
+		f, ok := got[field]
+		if !ok {
+			t.Errorf("missing error for %q in %+v", field, fields)
+			continue
+		}
+		if f.Rule != w.rule {
+			t.Errorf("%s: Rule = %q, want %q", field, f.Rule, w.rule)
+		}
+		if f.Message != w.message {
+			t.Errorf("%s: Message = %q, want %q", field, f.Message, w.message)
+		}
+	}
+}
+
+func TestFieldErrorsValid(t *testing.T) {
+	req := &testRequest{Name: "ok", Locale: "", Profile: &profile{Privacy: "friends"}, Ignored: "set"}
+	if err := validate(t, req); err != nil {
+		t.Fatalf("valid request failed validation: %v", err)
+	}
+}
+
+func TestFieldErrorsNotValidation(t *testing.T) {
+	if _, ok := FieldErrors(errors.New("unexpected EOF"), i18n.English); ok {
+		t.Error("FieldErrors should return false for non-validation errors")
+	}
+}
+
+func TestFieldErrorsFallbackMessage(t *testing.T) {
+	type choice struct {
+		Kind string `json:"kind" binding:"oneof=a b"`
+	}
+	fields, ok := FieldErrors(validate(t, &choice{Kind: "c"}), i18n.Japanese)
+	if !ok || len(fields) != 1 {
+		t.Fatalf("FieldErrors() = %+v, %v", fields, ok)
+	}
+	if want := "kind の値が不正です"; fields[0].Message != want {
+		t.Errorf("Message = %q, want fallback %q", fields[0].Message, want)
+	}
+	if fields[0].Rule != "oneof" {
+		t.Errorf("Rule = %q, want oneof", fields[0].Rule)
+	}
+}
@@ -0,0 +1,125 @@
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "seat_management"
+
+// Metrics はアプリケーションのPrometheusメトリクスを保持する
+// メトリクスを無効にしている場合はnilを渡せばよい（すべてのメソッドはnilでも安全に呼べる）
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpRequests  *prometheus.CounterVec
+	httpDuration  *prometheus.HistogramVec
+	webhookEvents *prometheus.CounterVec
+	userEvents    *prometheus.CounterVec
+	userSync      *prometheus.CounterVec
+}
+
+// New はメトリクスを作成し、Goランタイムとプロセスのコレクターと合わせて登録する
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "HTTPリクエスト数（ルートテンプレート別）",
+		}, []string{"method", "route", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTPリクエストの処理時間（ルートテンプレート別）",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+		webhookEvents: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "webhook_events_total",
+			Help:      "受信したWebhookイベント数（イベントタイプ・結果別）",
+		}, []string{"type", "outcome"}),
+		userEvents: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "user_events_total",
+			Help:      "ユーザーのライフサイクルイベント数",
+		}, []string{"action"}),
+		userSync: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "user_sync_total",
+			Help:      "外部認証基盤との同期で処理したユーザー数（結果別）",
+		}, []string{"outcome"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequests,
+		m.httpDuration,
+		m.webhookEvents,
+		m.userEvents,
+		m.userSync,
+	)
+	return m
+}
+
+// Handler は/metrics用のHTTPハンドラーを返す
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// RegisterDBStats はコネクションプールの統計を登録する
+func (m *Metrics) RegisterDBStats(db *sql.DB, name string) {
+	if m == nil {
+		return
+	}
+	m.registry.MustRegister(collectors.NewDBStatsCollector(db, name))
+}
+
+// Middleware はリクエスト数と処理時間を記録するginミドルウェア
+// ラベルにはパスではなくルートテンプレート（/api/admin/users/:id など）を使う
+func (m *Metrics) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		m.httpRequests.WithLabelValues(c.Request.Method, route, strconv.Itoa(c.Writer.Status())).Inc()
+		m.httpDuration.WithLabelValues(c.Request.Method, route).Observe(time.Since(start).Seconds())
+	}
+}
+
+// WebhookEvent はWebhookイベントの処理結果を記録する
+func (m *Metrics) WebhookEvent(eventType, outcome string) {
+	if m == nil {
+		return
+	}
+	m.webhookEvents.WithLabelValues(eventType, outcome).Inc()
+}
+
+// UserEvent はユーザーのライフサイクルイベント（created, deleted, suspendedなど）を記録する
+func (m *Metrics) UserEvent(action string) {
+	if m == nil {
+		return
+	}
+	m.userEvents.WithLabelValues(action).Inc()
+}
+
+// UserSynced は同期で処理したユーザー数を結果別に記録する
+func (m *Metrics) UserSynced(outcome string, n int) {
+	if m == nil || n == 0 {
+		return
+	}
+	m.userSync.WithLabelValues(outcome).Add(float64(n))
+}
@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"seat-management-backend/pkg/audit"
+)
+
+// setAuditActor は認証済みのユーザーを監査ログの操作者としてリクエストのコンテキストに設定する
+func setAuditActor(c *gin.Context) {
+	actor := audit.Actor{
+		Type:      audit.ActorUser,
+		IP:        c.ClientIP(),
+		RequestID: GetRequestID(c),
+	}
+	actor.ID, _ = GetClerkUserID(c)
+	actor.SessionID, _ = GetSessionID(c)
+	actor.Role, _ = GetOrganizationRole(c)
+	c.Request = c.Request.WithContext(audit.WithActor(c.Request.Context(), actor))
+}
+
+// WebhookAuditActor はWebhookによる変更の操作者（source）をコンテキストに設定するミドルウェア
+func WebhookAuditActor(source string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		actor := audit.Actor{
+			Type:      audit.ActorWebhook,
+			ID:        source,
+			IP:        c.ClientIP(),
+			RequestID: GetRequestID(c),
+		}
+		c.Request = c.Request.WithContext(audit.WithActor(c.Request.Context(), actor))
+		c.Next()
+	}
+}
@@ -1,25 +1,23 @@
 package middleware
 
 import (
-	"context"
 	"errors"
-	"fmt"
-	"net/http"
-	"os"
+	"log/slog"
 	"strings"
 
 	"github.com/clerk/clerk-sdk-go/v2"
 	"github.com/clerk/clerk-sdk-go/v2/jwt"
 	"github.com/gin-gonic/gin"
+
+	"seat-management-backend/pkg/config"
 )
 
 // InitClerk はClerkクライアントを初期化
-func InitClerk() error {
-	secretKey := os.Getenv("CLERK_SECRET_KEY")
-	if secretKey == "" {
+func InitClerk(cfg config.ClerkConfig) error {
+	if cfg.SecretKey == "" {
 		return errors.New("CLERK_SECRET_KEY is not set")
 	}
-	clerk.SetKey(secretKey)
+	clerk.SetKey(cfg.SecretKey.Value())
 	return nil
 }
 
@@ -29,50 +27,33 @@ func ClerkAuthMiddleware() gin.HandlerFunc {
 		// Authorizationヘッダーからトークンを取得
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "認証が必要です"})
-			c.Abort()
+			AbortWithError(c, ErrUnauthenticated)
 			return
 		}
 
 		// "Bearer "プレフィックスを削除
 		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
 		if tokenString == authHeader {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "無効な認証形式です"})
-			c.Abort()
+			AbortWithError(c, ErrInvalidToken)
 			return
 		}
 
-		// デバッグログ（開発時のみ）
-		if os.Getenv("GIN_MODE") == "debug" {
-			tokenPreview := tokenString
-			if len(tokenString) > 20 {
-				tokenPreview = tokenString[:20] + "..."
-			}
-			fmt.Printf("[DEBUG] Token received: %s\n", tokenPreview)
-		}
-
 		// セッショントークンを検証
-		ctx := context.Background()
+		// JWKSの取得がリクエストのトレースに含まれるよう、リクエストのコンテキストを渡す
+		ctx := c.Request.Context()
 
 		claims, err := jwt.Verify(ctx, &jwt.VerifyParams{
 			Token: tokenString,
 		})
 
 		if err != nil {
-			// デバッグログ
-			fmt.Printf("[ERROR] Token verification failed: %v\n", err)
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error":   "無効なトークンです",
-				"details": err.Error(),
-			})
-			c.Abort()
+			// 検証失敗の理由はクライアントに返さずログにのみ残す
+			slog.WarnContext(ctx, "token verification failed", "error", err)
+			AbortWithError(c, ErrInvalidToken)
 			return
 		}
 
-		// デバッグログ
-		if os.Getenv("GIN_MODE") == "debug" {
-			fmt.Printf("[DEBUG] Token verified successfully. Subject: %s\n", claims.Subject)
-		}
+		slog.DebugContext(ctx, "token verified", "clerk_user_id", claims.Subject)
 
 		// コンテキストにユーザー情報を設定
 		c.Set("clerkUserID", claims.Subject)
@@ -90,20 +71,24 @@ func ClerkAuthMiddleware() gin.HandlerFunc {
 			c.Set("organizationRole", claims.ActiveOrganizationRole)
 		}
 
+		// 以降の操作を監査ログにこのユーザーとして記録する
+		setAuditActor(c)
+
 		c.Next()
 	}
 }
 
 // GetClerkUserID はコンテキストからClerk User IDを取得
+// 認証されていない場合はErrUnauthenticatedを返す
 func GetClerkUserID(c *gin.Context) (string, error) {
 	userID, exists := c.Get("clerkUserID")
 	if !exists {
-		return "", errors.New("ユーザーIDが見つかりません")
+		return "", ErrUnauthenticated
 	}
 
 	userIDStr, ok := userID.(string)
 	if !ok {
-		return "", errors.New("無効なユーザーID形式です")
+		return "", ErrUnauthenticated
 	}
 
 	return userIDStr, nil
@@ -135,3 +120,21 @@ func GetName(c *gin.Context) (string, bool) {
 	}
 	return name.(string), true
 }
+
+// GetOrganizationID はコンテキストから有効な組織のIDを取得
+func GetOrganizationID(c *gin.Context) (string, bool) {
+	orgID, exists := c.Get("organizationID")
+	if !exists {
+		return "", false
+	}
+	return orgID.(string), true
+}
+
+// GetOrganizationRole はコンテキストから組織ロールを取得
+func GetOrganizationRole(c *gin.Context) (string, bool) {
+	role, exists := c.Get("organizationRole")
+	if !exists {
+		return "", false
+	}
+	return role.(string), true
+}
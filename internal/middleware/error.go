@@ -0,0 +1,161 @@
+package middleware
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"seat-management-backend/internal/domain/entity"
+	"seat-management-backend/internal/interface/validation"
+	"seat-management-backend/pkg/i18n"
+)
+
+// リクエスト単位のエラーコード（ドメインに属さないもの）
+const (
+	CodeUnauthenticated         entity.ErrorCode = "UNAUTHENTICATED"
+	CodeInvalidToken            entity.ErrorCode = "INVALID_TOKEN"
+	CodeForbidden               entity.ErrorCode = "FORBIDDEN"
+	CodeInvalidRequest          entity.ErrorCode = "INVALID_REQUEST"
+	CodeInvalidWebhookSignature entity.ErrorCode = "INVALID_WEBHOOK_SIGNATURE"
+	CodeValidationFailed        entity.ErrorCode = "VALIDATION_FAILED"
+	CodeInternal                entity.ErrorCode = "INTERNAL_ERROR"
+)
+
+var (
+	ErrUnauthenticated         = entity.NewError(CodeUnauthenticated)
+	ErrInvalidToken            = entity.NewError(CodeInvalidToken)
+	ErrForbidden               = entity.NewError(CodeForbidden)
+	ErrInvalidRequest          = entity.NewError(CodeInvalidRequest)
+	ErrInvalidWebhookSignature = entity.NewError(CodeInvalidWebhookSignature)
+	errValidationFailed        = entity.NewError(CodeValidationFailed)
+	errInternal                = entity.NewError(CodeInternal)
+)
+
+// codeStatus はエラーコードとHTTPステータスの対応
+// 登録されていないコードは500として扱う
+var codeStatus = map[entity.ErrorCode]int{
+	entity.CodeUserNotFound:          http.StatusNotFound,
+	entity.CodeInvalidEmail:          http.StatusBadRequest,
+	entity.CodeInvalidName:           http.StatusBadRequest,
+	entity.CodeInvalidLocale:         http.StatusBadRequest,
+	entity.CodeInvalidPrivacySetting: http.StatusBadRequest,
+	entity.CodeDuplicateEmail:        http.StatusConflict,
+	entity.CodeDuplicateClerkID:      http.StatusConflict,
+	entity.CodeUserSuspended:         http.StatusForbidden,
+	entity.CodeUserNotDeleted:        http.StatusConflict,
+	entity.CodeUserAnonymized:        http.StatusGone,
+	entity.CodeInvalidAuthProvider:   http.StatusBadRequest,
+	entity.CodeInvalidCursor:         http.StatusBadRequest,
+	entity.CodeVersionConflict:       http.StatusPreconditionFailed,
+	entity.CodeUnsupportedImageType:  http.StatusUnsupportedMediaType,
+	entity.CodeInvalidImage:          http.StatusUnprocessableEntity,
+	entity.CodeFileTooLarge:          http.StatusRequestEntityTooLarge,
+	entity.CodeDataExportNotFound:    http.StatusNotFound,
+	entity.CodeDataExportNotReady:    http.StatusConflict,
+	entity.CodeDataExportExpired:     http.StatusGone,
+
+	CodeUnauthenticated:         http.StatusUnauthorized,
+	CodeInvalidToken:            http.StatusUnauthorized,
+	CodeForbidden:               http.StatusForbidden,
+	CodeInvalidRequest:          http.StatusBadRequest,
+	CodeInvalidWebhookSignature: http.StatusBadRequest,
+	CodeValidationFailed:        http.StatusUnprocessableEntity,
+	CodeInternal:                http.StatusInternalServerError,
+}
+
+// Problem はRFC 7807形式のエラーレスポンス
+type Problem struct {
+	Type      string           `json:"type"`
+	Title     string           `json:"title"`
+	Status    int              `json:"status"`
+	Detail    string           `json:"detail,omitempty"`
+	Instance  string           `json:"instance,omitempty"`
+	Code      entity.ErrorCode `json:"code"`
+	RequestID string           `json:"request_id,omitempty"`
+	// 入力検証に失敗した項目（422の場合のみ）
+	Errors []validation.FieldError `json:"errors,omitempty"`
+}
+
+const problemContentType = "application/problem+json"
+
+// ErrorHandler はハンドラーがc.Errorで積んだエラーをproblem+jsonのレスポンスに変換するミドルウェア
+// 最後に積まれたエラーを使い、既にレスポンスが書き込まれている場合は何もしない
+// gin.ErrorTypeBindのエラーはリクエストの検証エラーとして扱う
+func ErrorHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+
+		last := c.Errors.Last()
+		if last.IsType(gin.ErrorTypeBind) {
+			respondBindError(c, last.Err)
+			return
+		}
+		respondProblem(c, last.Err)
+	}
+}
+
+// AbortWithError はエラーを積んで後続のハンドラーを中断する
+// レスポンスはErrorHandlerが書き込む
+func AbortWithError(c *gin.Context, err error) {
+	_ = c.Error(err)
+	c.Abort()
+}
+
+func respondProblem(c *gin.Context, err error) {
+	domainErr := toDomainError(err)
+	status, ok := codeStatus[domainErr.Code]
+	if !ok {
+		status = http.StatusInternalServerError
+	}
+
+	// 内部エラーの詳細はクライアントに返さずログにのみ残す
+	if status >= http.StatusInternalServerError {
+		slog.ErrorContext(c.Request.Context(), "request failed", "error", err)
+		domainErr = errInternal
+	}
+
+	writeProblem(c, status, domainErr)
+}
+
+// respondBindError は検証エラーを項目の一覧付きの422で返す
+// JSONの構文エラーなど項目に紐づかないエラーは400で返す
+func respondBindError(c *gin.Context, err error) {
+	fields, ok := validation.FieldErrors(err, i18n.FromContext(c.Request.Context()))
+	if !ok {
+		slog.DebugContext(c.Request.Context(), "failed to bind request", "error", err)
+		writeProblem(c, http.StatusBadRequest, ErrInvalidRequest)
+		return
+	}
+	writeProblem(c, http.StatusUnprocessableEntity, errValidationFailed, fields...)
+}
+
+func writeProblem(c *gin.Context, status int, domainErr *entity.Error, fields ...validation.FieldError) {
+	locale := i18n.FromContext(c.Request.Context())
+	c.Header("Content-Type", problemContentType)
+	c.Header("Content-Language", string(locale))
+	c.JSON(status, Problem{
+		Type:      "about:blank",
+		Title:     http.StatusText(status),
+		Status:    status,
+		Detail:    domainErr.Message(locale),
+		Instance:  c.Request.URL.Path,
+		Code:      domainErr.Code,
+		RequestID: GetRequestID(c),
+		Errors:    fields,
+	})
+}
+
+// toDomainError はエラーをコード付きのドメインエラーに変換する
+func toDomainError(err error) *entity.Error {
+	var domainErr *entity.Error
+	if errors.As(err, &domainErr) {
+		return domainErr
+	}
+	return errInternal
+}
@@ -0,0 +1,95 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"seat-management-backend/internal/domain/entity"
+	"seat-management-backend/pkg/i18n"
+)
+
+func TestEveryCodeHasMessages(t *testing.T) {
+	for code := range codeStatus {
+		for _, l := range i18n.Supported() {
+			if msg := i18n.T(l, string(code)); msg == string(code) {
+				t.Errorf("no %s message for %s", l, code)
+			}
+		}
+	}
+}
+
+func TestErrorHandlerLocalisesCodedErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		name       string
+		handler    gin.HandlerFunc
+		wantStatus int
+		wantCode   entity.ErrorCode
+	}{
+		{
+			name:       "invalid cursor",
+			handler:    func(c *gin.Context) { _ = c.Error(entity.ErrInvalidCursor) },
+			wantStatus: http.StatusBadRequest,
+			wantCode:   entity.CodeInvalidCursor,
+		},
+		{
+			name: "no authenticated user",
+			handler: func(c *gin.Context) {
+				if _, err := GetClerkUserID(c); err != nil {
+					_ = c.Error(err)
+				}
+			},
+			wantStatus: http.StatusUnauthorized,
+			wantCode:   CodeUnauthenticated,
+		},
+	}
+
+	for _, tt := range tests {
+		for _, l := range i18n.Supported() {
+			t.Run(tt.name+"/"+string(l), func(t *testing.T) {
+				r := gin.New()
+				r.Use(Locale(), ErrorHandler())
+				r.GET("/", tt.handler)
+
+				req := httptest.NewRequest(http.MethodGet, "/", nil)
+				req.Header.Set("Accept-Language", string(l))
+				rec := httptest.NewRecorder()
+				r.ServeHTTP(rec, req)
+
+				if rec.Code != tt.wantStatus {
+					t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+				}
+				var p Problem
+				if err := json.Unmarshal(rec.Body.Bytes(), &p); err != nil {
+					t.Fatalf("decode problem: %v", err)
+				}
+				if p.Code != tt.wantCode {
+					t.Errorf("code = %s, want %s", p.Code, tt.wantCode)
+				}
+				if want := i18n.T(l, string(tt.wantCode)); p.Detail != want {
+					t.Errorf("detail = %q, want %q", p.Detail, want)
+				}
+			})
+		}
+	}
+}
+
+func TestGetClerkUserID(t *testing.T) {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	if _, err := GetClerkUserID(c); !errors.Is(err, ErrUnauthenticated) {
+		t.Errorf("without a user: err = %v, want ErrUnauthenticated", err)
+	}
+	c.Set("clerkUserID", 42)
+	if _, err := GetClerkUserID(c); !errors.Is(err, ErrUnauthenticated) {
+		t.Errorf("with a non-string ID: err = %v, want ErrUnauthenticated", err)
+	}
+	c.Set("clerkUserID", "user_123")
+	if id, err := GetClerkUserID(c); err != nil || id != "user_123" {
+		t.Errorf("GetClerkUserID = %q, %v; want user_123", id, err)
+	}
+}
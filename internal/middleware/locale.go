@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"seat-management-backend/pkg/i18n"
+)
+
+// Locale はAccept-Languageヘッダーからメッセージの言語を決めてコンテキストに設定するミドルウェア
+// ユーザーが言語を設定している場合は、ユーザーを取得した後にSetLocaleで上書きする
+func Locale() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		SetLocale(c, i18n.MatchAcceptLanguage(c.GetHeader("Accept-Language")))
+		c.Next()
+	}
+}
+
+// SetLocale はリクエストのメッセージの言語を設定する
+func SetLocale(c *gin.Context, locale i18n.Locale) {
+	c.Request = c.Request.WithContext(i18n.WithLocale(c.Request.Context(), locale))
+}
@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestLogger はリクエストごとのアクセスログを出力するミドルウェア
+// RequestIDの後に登録する
+func RequestLogger() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		status := c.Writer.Status()
+		level := slog.LevelInfo
+		switch {
+		case status >= http.StatusInternalServerError:
+			level = slog.LevelError
+		case status >= http.StatusBadRequest:
+			level = slog.LevelWarn
+		}
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+
+		attrs := []slog.Attr{
+			slog.String("method", c.Request.Method),
+			slog.String("route", route),
+			slog.String("path", c.Request.URL.Path),
+			slog.Int("status", status),
+			slog.Int64("latency_ms", time.Since(start).Milliseconds()),
+			slog.String("client_ip", c.ClientIP()),
+			slog.Int("size", c.Writer.Size()),
+		}
+		if len(c.Errors) > 0 {
+			attrs = append(attrs, slog.String("errors", c.Errors.String()))
+		}
+
+		slog.LogAttrs(c.Request.Context(), level, "http request", attrs...)
+	}
+}
+
+// Recovery はpanicを回復してslogに記録し、500を返すミドルウェア
+func Recovery() gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(nil, func(c *gin.Context, recovered any) {
+		// レスポンスの途中で中断する場合はnet/httpに接続を切らせる
+		if recovered == http.ErrAbortHandler {
+			panic(recovered)
+		}
+		slog.ErrorContext(c.Request.Context(), "panic recovered",
+			"panic", recovered, "stack", string(debug.Stack()))
+		c.Abort()
+		writeProblem(c, http.StatusInternalServerError, errInternal)
+	})
+}
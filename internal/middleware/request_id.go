@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+
+	"seat-management-backend/pkg/logger"
+	ulidpkg "seat-management-backend/pkg/ulid"
+)
+
+const RequestIDHeader = "X-Request-ID"
+
+// 外部から受け取るリクエストIDとして許可する形式
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9\-_.]{1,128}$`)
+
+// RequestID はX-Request-IDを引き継ぐか新たに発行し、
+// リクエストのコンテキストとレスポンスヘッダーに設定するミドルウェア
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !requestIDPattern.MatchString(requestID) {
+			requestID = ulidpkg.Generate()
+		}
+
+		c.Set("requestID", requestID)
+		c.Request = c.Request.WithContext(logger.WithRequestID(c.Request.Context(), requestID))
+		c.Header(RequestIDHeader, requestID)
+
+		c.Next()
+	}
+}
+
+// GetRequestID はコンテキストからリクエストIDを取得
+func GetRequestID(c *gin.Context) string {
+	return c.GetString("requestID")
+}
@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gin-gonic/gin"
+
+	"seat-management-backend/internal/domain/entity"
+)
+
+// OrgRoleAdmin はClerkの組織管理者ロール
+const OrgRoleAdmin = "org:admin"
+
+// RequireOrganizationRole は組織orgIDでの組織ロールが指定のいずれかであることを要求するミドルウェア
+// 他の組織の管理者を通さないよう、セッションの有効な組織がorgIDと一致することも確認する
+// orgIDが空の場合はすべてのリクエストを拒否する
+// ClerkAuthMiddlewareの後に使用する
+func RequireOrganizationRole(orgID string, roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		activeOrgID, ok := GetOrganizationID(c)
+		if !ok || orgID == "" || activeOrgID != orgID {
+			AbortWithError(c, ErrForbidden)
+			return
+		}
+
+		role, ok := GetOrganizationRole(c)
+		if !ok {
+			AbortWithError(c, ErrForbidden)
+			return
+		}
+
+		for _, r := range roles {
+			if role == r {
+				c.Next()
+				return
+			}
+		}
+
+		AbortWithError(c, ErrForbidden)
+	}
+}
+
+// UserFinder はClerk User IDからユーザーを取得する
+type UserFinder interface {
+	GetByClerkUserID(ctx context.Context, clerkUserID string) (*entity.User, error)
+}
+
+// RequireActiveUser は利用停止中のユーザーを拒否するミドルウェア
+// 未登録のユーザーも操作を許可しない
+// ClerkAuthMiddlewareの後に使用する
+func RequireActiveUser(users UserFinder) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		clerkUserID, err := GetClerkUserID(c)
+		if err != nil {
+			AbortWithError(c, ErrUnauthenticated)
+			return
+		}
+
+		user, err := users.GetByClerkUserID(c.Request.Context(), clerkUserID)
+		if err != nil {
+			if errors.Is(err, entity.ErrUserNotFound) {
+				err = ErrForbidden
+			}
+			AbortWithError(c, err)
+			return
+		}
+		if user.IsSuspended() {
+			AbortWithError(c, entity.ErrUserSuspended)
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,130 @@
+package usecase
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"time"
+
+	"seat-management-backend/internal/domain/entity"
+	"seat-management-backend/internal/domain/repository"
+	"seat-management-backend/pkg/audit"
+	"seat-management-backend/pkg/pagination"
+)
+
+// auditCSVColumns はCSVの列
+var auditCSVColumns = []string{
+	"id", "occurred_at", "actor_type", "actor_id", "actor_role", "action",
+	"target_type", "target_id", "ip_address", "session_id", "request_id", "changes",
+}
+
+// AuditUsecase は監査ログの記録と検索を定義
+type AuditUsecase interface {
+	// Record は操作を記録する。操作者はコンテキストから取得する
+	// 記録に失敗しても元の操作は取り消さず、エラーログを残す
+	Record(ctx context.Context, action entity.AuditAction, targetType, targetID string, changes audit.Changes)
+	List(ctx context.Context, filter repository.AuditEventFilter, cursor string, limit int) (*pagination.Page[*entity.AuditEvent], error)
+	// ScrubActor は個人情報を消去するユーザーの操作の記録から、Clerk User IDなど個人を特定できる値を消す
+	ScrubActor(ctx context.Context, clerkUserID, replacement string) error
+	// WriteCSV は条件に一致する監査ログをすべて新しい順にCSVで書き込む
+	// 全件をメモリに載せないよう1ページずつ書き込み、wがFlushを持つ場合はページごとに呼ぶ
+	WriteCSV(ctx context.Context, filter repository.AuditEventFilter, w io.Writer) error
+}
+
+type auditUsecase struct {
+	auditRepo repository.AuditEventRepository
+}
+
+// NewAuditUsecase はAuditUsecaseの新しいインスタンスを作成
+func NewAuditUsecase(ar repository.AuditEventRepository) AuditUsecase {
+	return &auditUsecase{auditRepo: ar}
+}
+
+func (u *auditUsecase) Record(ctx context.Context, action entity.AuditAction, targetType, targetID string, changes audit.Changes) {
+	actor := audit.ActorFromContext(ctx)
+	event := &entity.AuditEvent{
+		OccurredAt: time.Now(),
+		ActorType:  actor.Type,
+		ActorID:    optionalString(actor.ID),
+		ActorRole:  optionalString(actor.Role),
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   optionalString(targetID),
+		IPAddress:  optionalString(actor.IP),
+		SessionID:  optionalString(actor.SessionID),
+		RequestID:  optionalString(actor.RequestID),
+	}
+	if len(changes) > 0 {
+		event.Changes = changes
+	}
+
+	// 呼び出し元のリクエストがキャンセルされても記録は残す
+	if err := u.auditRepo.Create(context.WithoutCancel(ctx), event); err != nil {
+		slog.ErrorContext(ctx, "audit: failed to record event",
+			"action", action, "target_type", targetType, "target_id", targetID, "error", err)
+	}
+}
+
+func (u *auditUsecase) List(ctx context.Context, filter repository.AuditEventFilter, cursor string, limit int) (*pagination.Page[*entity.AuditEvent], error) {
+	limit = pagination.NormalizeLimit(limit)
+	return u.auditRepo.List(ctx, filter, cursor, limit)
+}
+
+func (u *auditUsecase) ScrubActor(ctx context.Context, clerkUserID, replacement string) error {
+	return u.auditRepo.ScrubActor(ctx, clerkUserID, replacement)
+}
+
+func (u *auditUsecase) WriteCSV(ctx context.Context, filter repository.AuditEventFilter, w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(auditCSVColumns); err != nil {
+		return err
+	}
+
+	flusher, _ := w.(interface{ Flush() })
+	cursor := ""
+	for {
+		page, err := u.auditRepo.List(ctx, filter, cursor, pagination.MaxLimit)
+		if err != nil {
+			return err
+		}
+		for _, event := range page.Items {
+			if err := cw.Write(auditCSVRow(event)); err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		if err := cw.Error(); err != nil {
+			return err
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		if page.NextCursor == "" {
+			return nil
+		}
+		cursor = page.NextCursor
+	}
+}
+
+func auditCSVRow(e *entity.AuditEvent) []string {
+	changes := ""
+	if len(e.Changes) > 0 {
+		if b, err := json.Marshal(e.Changes); err == nil {
+			changes = string(b)
+		}
+	}
+	return []string{
+		e.ID, e.OccurredAt.UTC().Format(time.RFC3339), string(e.ActorType), csvString(e.ActorID), csvString(e.ActorRole),
+		string(e.Action), e.TargetType, csvString(e.TargetID), csvString(e.IPAddress), csvString(e.SessionID),
+		csvString(e.RequestID), changes,
+	}
+}
+
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
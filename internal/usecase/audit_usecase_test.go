@@ -0,0 +1,78 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"testing"
+	"time"
+
+	"seat-management-backend/internal/domain/entity"
+	"seat-management-backend/internal/domain/repository"
+	"seat-management-backend/pkg/pagination"
+)
+
+// flushRecorder はFlushの回数を数えるWriter
+type flushRecorder struct {
+	bytes.Buffer
+	flushes int
+}
+
+func (w *flushRecorder) Flush() { w.flushes++ }
+
+func seedAuditEvents(repo *fakeAuditEventRepository, n int) {
+	for i := 1; i <= n; i++ {
+		target := testID(i)
+		_ = repo.Create(context.Background(), &entity.AuditEvent{
+			OccurredAt: time.Date(2026, 1, 1, 0, 0, i, 0, time.UTC),
+			ActorType:  "system",
+			Action:     entity.AuditUserUpdated,
+			TargetType: entity.AuditTargetUser,
+			TargetID:   &target,
+		})
+	}
+}
+
+func TestAuditWriteCSVStreamsAllPages(t *testing.T) {
+	repo := &fakeAuditEventRepository{}
+	total := pagination.MaxLimit*2 + 5
+	seedAuditEvents(repo, total)
+
+	var w flushRecorder
+	if err := NewAuditUsecase(repo).WriteCSV(context.Background(), repository.AuditEventFilter{}, &w); err != nil {
+		t.Fatalf("WriteCSV() returned error: %v", err)
+	}
+
+	rows, err := csv.NewReader(&w.Buffer).ReadAll()
+	if err != nil {
+		t.Fatalf("output is not valid CSV: %v", err)
+	}
+	if len(rows) != total+1 {
+		t.Fatalf("got %d rows, want header + %d events", len(rows), total)
+	}
+	if rows[0][0] != "id" {
+		t.Errorf("first row should be the header, got %v", rows[0])
+	}
+	if rows[1][0] != testID(total) || rows[total][0] != testID(1) {
+		t.Errorf("rows should be newest first, got %s ... %s", rows[1][0], rows[total][0])
+	}
+	if w.flushes != 3 {
+		t.Errorf("flushed %d times, want once per page (3)", w.flushes)
+	}
+}
+
+func TestAuditWriteCSVReturnsErrorMidStream(t *testing.T) {
+	wantErr := errors.New("replica went away")
+	repo := &fakeAuditEventRepository{listErr: wantErr, listErrAfter: 1}
+	seedAuditEvents(repo, pagination.MaxLimit+1)
+
+	var w flushRecorder
+	err := NewAuditUsecase(repo).WriteCSV(context.Background(), repository.AuditEventFilter{}, &w)
+	if !errors.Is(err, wantErr) {
+		t.Fatalf("WriteCSV() error = %v, want %v", err, wantErr)
+	}
+	if w.flushes != 1 {
+		t.Errorf("flushed %d times, want only the first page", w.flushes)
+	}
+}
@@ -0,0 +1,166 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path"
+	"strconv"
+	"strings"
+
+	"seat-management-backend/internal/domain/entity"
+	"seat-management-backend/internal/domain/repository"
+	"seat-management-backend/pkg/imageproc"
+	"seat-management-backend/pkg/storage"
+	"seat-management-backend/pkg/tracing"
+	"seat-management-backend/pkg/ulid"
+)
+
+// AvatarSizes はアバター画像を生成するサイズ（正方形の一辺のピクセル数）
+var AvatarSizes = []int{64, 128, 256, 512}
+
+// AvatarKeyPrefix はアバター画像を保存するキーの先頭部分
+// この下のオブジェクトは誰でも取得できるURLで配信される
+const AvatarKeyPrefix = "avatars"
+
+// avatarDefaultSize はUser.AvatarURLに設定するサイズ
+const avatarDefaultSize = 256
+
+// AvatarUploadResult はアバター画像のアップロード結果
+type AvatarUploadResult struct {
+	User *entity.User `json:"user"`
+	// サイズ（"64"など）ごとの画像のURL
+	URLs map[string]string `json:"urls"`
+}
+
+// AvatarUsecase はアバター画像のアップロードを定義
+type AvatarUsecase interface {
+	Upload(ctx context.Context, user *entity.User, data []byte) (*AvatarUploadResult, error)
+	// IsUploaded はユーザーのアバターがアップロードされた画像かどうかを返す
+	// アップロードされた画像はClerkのプロフィール画像で上書きしない
+	IsUploaded(user *entity.User) bool
+}
+
+type avatarUsecase struct {
+	userRepo repository.UserRepository
+	audit    AuditUsecase
+	store    storage.BlobStore
+}
+
+// NewAvatarUsecase はAvatarUsecaseの新しいインスタンスを作成
+func NewAvatarUsecase(ur repository.UserRepository, au AuditUsecase, store storage.BlobStore) AvatarUsecase {
+	return &avatarUsecase{
+		userRepo: ur,
+		audit:    au,
+		store:    store,
+	}
+}
+
+// Upload は画像を検証して標準サイズに変換し、ユーザーのアバターに設定する
+// 以前にアップロードした画像は設定の更新後に削除する
+func (u *avatarUsecase) Upload(ctx context.Context, user *entity.User, data []byte) (_ *AvatarUploadResult, err error) {
+	ctx, span := tracing.Start(ctx, "AvatarUsecase.Upload")
+	defer func() { tracing.End(span, err) }()
+
+	if _, err := imageproc.DetectType(data); err != nil {
+		return nil, entity.ErrUnsupportedImageType
+	}
+	img, err := imageproc.Decode(data)
+	if err != nil {
+		if errors.Is(err, imageproc.ErrInvalidImage) {
+			return nil, entity.ErrInvalidImage
+		}
+		return nil, err
+	}
+
+	// 同じURLでキャッシュされないよう、アップロードごとに別のキーにする
+	prefix := path.Join(AvatarKeyPrefix, user.ID, ulid.Generate())
+	urls := make(map[string]string, len(AvatarSizes))
+	var keys []string
+	for _, size := range AvatarSizes {
+		resized, err := imageproc.SquareJPEG(img, size)
+		if err != nil {
+			u.deleteKeys(ctx, keys)
+			return nil, fmt.Errorf("failed to resize avatar: %w", err)
+		}
+
+		key := avatarKey(prefix, size)
+		opts := storage.PutOptions{ContentType: imageproc.OutputContentType, CacheControl: storage.CacheControlImmutable}
+		if err := u.store.Put(ctx, key, bytes.NewReader(resized), int64(len(resized)), opts); err != nil {
+			u.deleteKeys(ctx, keys)
+			return nil, fmt.Errorf("failed to store avatar: %w", err)
+		}
+		keys = append(keys, key)
+		urls[strconv.Itoa(size)] = u.store.URL(key)
+	}
+
+	before := *user
+	previous := user.AvatarURL
+	avatarURL := urls[strconv.Itoa(avatarDefaultSize)]
+	user.AvatarURL = &avatarURL
+	if err := u.userRepo.Update(ctx, user, entity.UserColumnAvatarURL); err != nil {
+		user.AvatarURL = previous
+		u.deleteKeys(ctx, keys)
+		return nil, err
+	}
+	u.audit.Record(ctx, entity.AuditUserUpdated, entity.AuditTargetUser, user.ID, entity.UserAuditChanges(&before, user))
+
+	// 以前にアップロードした画像は不要になるため削除する
+	if previous != nil {
+		deleteAvatarObjects(ctx, u.store, user.ID, *previous)
+	}
+
+	return &AvatarUploadResult{User: user, URLs: urls}, nil
+}
+
+func (u *avatarUsecase) IsUploaded(user *entity.User) bool {
+	return user.AvatarURL != nil && isUploadedAvatar(u.store, user.ID, *user.AvatarURL)
+}
+
+// isUploadedAvatar はURLがユーザーuserIDのアップロードしたアバター画像を指すかどうかを返す
+func isUploadedAvatar(store storage.BlobStore, userID, url string) bool {
+	_, ok := uploadedAvatarKey(store, userID, url)
+	return ok
+}
+
+// uploadedAvatarKey はURLがユーザーuserIDのアップロードしたアバター画像を指す場合にそのキーを返す
+// Clerkの画像など、このストアのものでないURLの場合はfalse
+func uploadedAvatarKey(store storage.BlobStore, userID, url string) (string, bool) {
+	key, ok := store.KeyFromURL(url)
+	if !ok || !strings.HasPrefix(key, path.Join(AvatarKeyPrefix, userID)+"/") {
+		return "", false
+	}
+	return key, true
+}
+
+// deleteAvatarObjects はURLが指すアバター画像（すべてのサイズ）を削除する
+// Clerkの画像など、このストアのものでないURLは対象外
+func deleteAvatarObjects(ctx context.Context, store storage.BlobStore, userID, url string) {
+	key, ok := uploadedAvatarKey(store, userID, url)
+	if !ok {
+		return
+	}
+
+	prefix := path.Dir(key)
+	for _, size := range AvatarSizes {
+		key := avatarKey(prefix, size)
+		if err := store.Delete(ctx, key); err != nil {
+			slog.WarnContext(ctx, "failed to delete avatar object", "key", key, "error", err)
+		}
+	}
+}
+
+// deleteKeys はオブジェクトを削除する（失敗してもログに残すだけで処理は続ける）
+func (u *avatarUsecase) deleteKeys(ctx context.Context, keys []string) {
+	for _, key := range keys {
+		if err := u.store.Delete(ctx, key); err != nil {
+			slog.WarnContext(ctx, "failed to delete avatar object", "key", key, "error", err)
+		}
+	}
+}
+
+func avatarKey(prefix string, size int) string {
+	return fmt.Sprintf("%s/%d.jpg", prefix, size)
+}
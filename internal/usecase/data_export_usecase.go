@@ -0,0 +1,502 @@
+package usecase
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path"
+	"slices"
+	"time"
+
+	"seat-management-backend/internal/domain/entity"
+	"seat-management-backend/internal/domain/repository"
+	"seat-management-backend/pkg/audit"
+	"seat-management-backend/pkg/pagination"
+	"seat-management-backend/pkg/storage"
+	"seat-management-backend/pkg/tracing"
+)
+
+// DataExportKeyPrefix はエクスポートしたアーカイブを保存するキーの先頭部分
+// 個人データを含むため、公開URLで配信してはならない
+const DataExportKeyPrefix = "exports"
+
+// DataExportContentType はアーカイブのContent-Type
+const DataExportContentType = "application/zip"
+
+// dataExportFormatVersion はアーカイブの形式のバージョン（manifest.jsonに記録する）
+const dataExportFormatVersion = 1
+
+// dataExportStaleAfter は処理中のまま終わらないジョブを再実行するまでの時間
+const dataExportStaleAfter = 30 * time.Minute
+
+// dataExportPurgeBatchSize は期限切れのアーカイブを一度に削除する件数
+const dataExportPurgeBatchSize = 100
+
+// dataExportFailureSaveTimeout は失敗したジョブの状態を保存するまでの時間の上限
+const dataExportFailureSaveTimeout = 10 * time.Second
+
+// DataExportUsecase は個人データのエクスポートを定義
+type DataExportUsecase interface {
+	// RequiresAsync はアーカイブの作成を非同期ジョブにすべきかどうかを返す
+	RequiresAsync(ctx context.Context, user *entity.User) (bool, error)
+	// WriteArchive はアーカイブをその場で作成してwに書き込む
+	WriteArchive(ctx context.Context, user *entity.User, w io.Writer) error
+	// Request は非同期ジョブを作成する（処理待ち・処理中のものがあればそれを返す）
+	Request(ctx context.Context, user *entity.User) (*entity.DataExport, error)
+	Get(ctx context.Context, userID, id string) (*entity.DataExport, error)
+	// Open は完了したジョブのアーカイブを開く
+	Open(ctx context.Context, userID, id string) (io.ReadCloser, *entity.DataExport, error)
+	// ProcessNext は処理待ちのジョブを1件処理する（処理するものがなければfalse）
+	ProcessNext(ctx context.Context) (bool, error)
+	// PurgeExpired は保存期間を過ぎたアーカイブを削除し、削除した件数を返す
+	PurgeExpired(ctx context.Context) (int, error)
+}
+
+type dataExportUsecase struct {
+	userRepo       repository.UserRepository
+	exportRepo     repository.DataExportRepository
+	auditRepo      repository.AuditEventRepository
+	store          storage.BlobStore
+	sections       []exportSection
+	syncMaxRecords int
+	retention      time.Duration
+}
+
+// NewDataExportUsecase はDataExportUsecaseの新しいインスタンスを作成
+// レコード数がsyncMaxRecordsを超えるアカウントは非同期ジョブで作成し、retentionの間保存する
+func NewDataExportUsecase(ur repository.UserRepository, er repository.DataExportRepository, ar repository.AuditEventRepository, store storage.BlobStore, syncMaxRecords int, retention time.Duration) DataExportUsecase {
+	u := &dataExportUsecase{
+		userRepo:       ur,
+		exportRepo:     er,
+		auditRepo:      ar,
+		store:          store,
+		syncMaxRecords: syncMaxRecords,
+		retention:      retention,
+	}
+	u.sections = append(slices.Clone(dataExportSections), u.activitySection())
+	return u
+}
+
+// exportSection はアーカイブに含めるデータの種類
+// 各セクションは <name>.json と <name>.csv として出力される
+type exportSection struct {
+	name    string
+	columns []string
+	// count はレコード数を返す（同期・非同期の判定に使う）
+	count func(ctx context.Context, user *entity.User) (int, error)
+	// collect はJSONに出力する値とCSVの行を返す
+	collect func(ctx context.Context, user *entity.User) (any, [][]string, error)
+}
+
+// exportProfile はprofile.jsonに出力するユーザー情報
+type exportProfile struct {
+	ID                  string     `json:"id"`
+	ClerkUserID         string     `json:"clerk_user_id"`
+	Email               string     `json:"email"`
+	Name                string     `json:"name"`
+	AvatarURL           *string    `json:"avatar_url"`
+	PrimaryAuthProvider string     `json:"primary_auth_provider"`
+	Locale              *string    `json:"locale"`
+	SuspendedAt         *time.Time `json:"suspended_at"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+}
+
+type exportLogin struct {
+	LoggedInAt time.Time `json:"logged_in_at"`
+}
+
+type exportPrivacySettings struct {
+	DefaultPrivacySetting string `json:"default_privacy_setting"`
+}
+
+// exportActivity はactivity.jsonに出力するアカウントへの操作の記録
+// 他の利用者（管理者など）を特定できる操作者のIDやIPアドレスは含めない
+type exportActivity struct {
+	OccurredAt time.Time          `json:"occurred_at"`
+	Action     entity.AuditAction `json:"action"`
+	ActorType  audit.ActorType    `json:"actor_type"`
+	Changes    audit.Changes      `json:"changes,omitempty"`
+}
+
+// dataExportSections はエクスポートの対象
+// 予約や友達関係を追加した場合はここにセクションを追加する
+var dataExportSections = []exportSection{
+	{
+		name: "profile",
+		columns: []string{
+			"id", "clerk_user_id", "email", "name", "avatar_url", "primary_auth_provider",
+			"locale", "suspended_at", "created_at", "updated_at",
+		},
+		count: func(context.Context, *entity.User) (int, error) { return 1, nil },
+		collect: func(_ context.Context, user *entity.User) (any, [][]string, error) {
+			p := exportProfile{
+				ID:                  user.ID,
+				ClerkUserID:         user.ClerkUserID,
+				Email:               user.Email,
+				Name:                user.Name,
+				AvatarURL:           user.AvatarURL,
+				PrimaryAuthProvider: string(user.PrimaryAuthProvider),
+				Locale:              user.Locale,
+				SuspendedAt:         user.SuspendedAt,
+				CreatedAt:           user.CreatedAt,
+				UpdatedAt:           user.UpdatedAt,
+			}
+			row := []string{
+				p.ID, p.ClerkUserID, p.Email, p.Name, csvString(p.AvatarURL), p.PrimaryAuthProvider,
+				csvString(p.Locale), csvTime(p.SuspendedAt), csvTime(&p.CreatedAt), csvTime(&p.UpdatedAt),
+			}
+			return p, [][]string{row}, nil
+		},
+	},
+	{
+		// ログインの記録は最終ログイン時刻のみ保持している
+		name:    "login_history",
+		columns: []string{"logged_in_at"},
+		count: func(_ context.Context, user *entity.User) (int, error) {
+			if user.LastLoginAt == nil {
+				return 0, nil
+			}
+			return 1, nil
+		},
+		collect: func(_ context.Context, user *entity.User) (any, [][]string, error) {
+			logins := []exportLogin{}
+			var rows [][]string
+			if user.LastLoginAt != nil {
+				logins = append(logins, exportLogin{LoggedInAt: *user.LastLoginAt})
+				rows = append(rows, []string{csvTime(user.LastLoginAt)})
+			}
+			return logins, rows, nil
+		},
+	},
+	{
+		name:    "privacy_settings",
+		columns: []string{"default_privacy_setting"},
+		count:   func(context.Context, *entity.User) (int, error) { return 1, nil },
+		collect: func(_ context.Context, user *entity.User) (any, [][]string, error) {
+			s := exportPrivacySettings{DefaultPrivacySetting: string(user.DefaultPrivacySetting)}
+			return s, [][]string{{s.DefaultPrivacySetting}}, nil
+		},
+	},
+}
+
+// exportManifest はアーカイブの内容の説明（manifest.json）
+type exportManifest struct {
+	FormatVersion int                   `json:"format_version"`
+	UserID        string                `json:"user_id"`
+	GeneratedAt   time.Time             `json:"generated_at"`
+	Sections      []exportManifestEntry `json:"sections"`
+}
+
+type exportManifestEntry struct {
+	Name    string   `json:"name"`
+	Records int      `json:"records"`
+	Files   []string `json:"files"`
+}
+
+// activitySection はアカウントへの操作の記録（監査ログ）のセクションを返す
+// 利用を続けるほど増えるため、大きなアカウントは主にこのセクションで非同期になる
+func (u *dataExportUsecase) activitySection() exportSection {
+	filter := func(user *entity.User) repository.AuditEventFilter {
+		return repository.AuditEventFilter{TargetType: entity.AuditTargetUser, TargetID: user.ID}
+	}
+	return exportSection{
+		name:    "activity",
+		columns: []string{"occurred_at", "action", "actor_type", "changes"},
+		count: func(ctx context.Context, user *entity.User) (int, error) {
+			n, err := u.auditRepo.Count(ctx, filter(user))
+			return int(n), err
+		},
+		collect: func(ctx context.Context, user *entity.User) (any, [][]string, error) {
+			activities := []exportActivity{}
+			var rows [][]string
+			cursor := ""
+			for {
+				page, err := u.auditRepo.List(ctx, filter(user), cursor, pagination.MaxLimit)
+				if err != nil {
+					return nil, nil, err
+				}
+				for _, e := range page.Items {
+					a := exportActivity{OccurredAt: e.OccurredAt, Action: e.Action, ActorType: e.ActorType, Changes: e.Changes}
+					changes := ""
+					if len(a.Changes) > 0 {
+						b, err := json.Marshal(a.Changes)
+						if err != nil {
+							return nil, nil, err
+						}
+						changes = string(b)
+					}
+					activities = append(activities, a)
+					rows = append(rows, []string{csvTime(&a.OccurredAt), string(a.Action), string(a.ActorType), changes})
+				}
+				if page.NextCursor == "" {
+					return activities, rows, nil
+				}
+				cursor = page.NextCursor
+			}
+		},
+	}
+}
+
+func (u *dataExportUsecase) RequiresAsync(ctx context.Context, user *entity.User) (bool, error) {
+	total := 0
+	for _, section := range u.sections {
+		n, err := section.count(ctx, user)
+		if err != nil {
+			return false, fmt.Errorf("failed to count %s: %w", section.name, err)
+		}
+		total += n
+	}
+	return total > u.syncMaxRecords, nil
+}
+
+func (u *dataExportUsecase) WriteArchive(ctx context.Context, user *entity.User, w io.Writer) (err error) {
+	ctx, span := tracing.Start(ctx, "DataExportUsecase.WriteArchive")
+	defer func() { tracing.End(span, err) }()
+
+	zw := zip.NewWriter(w)
+	manifest := exportManifest{
+		FormatVersion: dataExportFormatVersion,
+		UserID:        user.ID,
+		GeneratedAt:   time.Now().UTC(),
+	}
+
+	for _, section := range u.sections {
+		value, rows, err := section.collect(ctx, user)
+		if err != nil {
+			return fmt.Errorf("failed to collect %s: %w", section.name, err)
+		}
+
+		jsonName := section.name + ".json"
+		if err := writeZipJSON(zw, jsonName, value); err != nil {
+			return err
+		}
+		csvName := section.name + ".csv"
+		if err := writeZipCSV(zw, csvName, section.columns, rows); err != nil {
+			return err
+		}
+
+		manifest.Sections = append(manifest.Sections, exportManifestEntry{
+			Name:    section.name,
+			Records: len(rows),
+			Files:   []string{jsonName, csvName},
+		})
+	}
+
+	if err := writeZipJSON(zw, "manifest.json", manifest); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+func (u *dataExportUsecase) Request(ctx context.Context, user *entity.User) (*entity.DataExport, error) {
+	active, err := u.exportRepo.FindActiveByUserID(ctx, user.ID)
+	if err == nil {
+		return active, nil
+	}
+	if !errors.Is(err, entity.ErrDataExportNotFound) {
+		return nil, err
+	}
+
+	export := &entity.DataExport{
+		UserID: user.ID,
+		Status: entity.DataExportPending,
+	}
+	if err := u.exportRepo.Create(ctx, export); err != nil {
+		return nil, err
+	}
+	slog.InfoContext(ctx, "data export requested", "user_id", user.ID, "export_id", export.ID)
+	return export, nil
+}
+
+func (u *dataExportUsecase) Get(ctx context.Context, userID, id string) (*entity.DataExport, error) {
+	return u.exportRepo.FindByID(ctx, userID, id)
+}
+
+func (u *dataExportUsecase) Open(ctx context.Context, userID, id string) (io.ReadCloser, *entity.DataExport, error) {
+	export, err := u.exportRepo.FindByID(ctx, userID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch export.Status {
+	case entity.DataExportCompleted:
+	case entity.DataExportExpired:
+		return nil, nil, entity.ErrDataExportExpired
+	default:
+		return nil, nil, entity.ErrDataExportNotReady
+	}
+	if export.ExpiresAt != nil && time.Now().After(*export.ExpiresAt) {
+		return nil, nil, entity.ErrDataExportExpired
+	}
+
+	rc, err := u.store.Get(ctx, *export.ObjectKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, nil, entity.ErrDataExportExpired
+		}
+		return nil, nil, err
+	}
+	return rc, export, nil
+}
+
+func (u *dataExportUsecase) ProcessNext(ctx context.Context) (_ bool, err error) {
+	export, err := u.exportRepo.ClaimNext(ctx, dataExportStaleAfter)
+	if err != nil {
+		return false, err
+	}
+	if export == nil {
+		return false, nil
+	}
+
+	ctx, span := tracing.Start(ctx, "DataExportUsecase.Process")
+	defer func() { tracing.End(span, err) }()
+
+	if err := u.process(ctx, export); err != nil {
+		if errors.Is(err, entity.ErrVersionConflict) {
+			// 処理に時間がかかっている間に別のワーカーが取り直した。結果はそちらに任せる
+			slog.WarnContext(ctx, "data export was reclaimed by another worker", "export_id", export.ID)
+			return true, nil
+		}
+		slog.ErrorContext(ctx, "data export failed", "export_id", export.ID, "user_id", export.UserID, "error", err)
+		msg := err.Error()
+		export.Status = entity.DataExportFailed
+		export.Error = &msg
+		now := time.Now()
+		export.CompletedAt = &now
+		// 停止や期限切れでctxが取り消されて失敗した場合も、失敗したことは保存する
+		saveCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), dataExportFailureSaveTimeout)
+		defer cancel()
+		if saveErr := u.exportRepo.UpdateStatus(saveCtx, export, entity.DataExportProcessing); saveErr != nil {
+			return true, errors.Join(err, saveErr)
+		}
+		return true, err
+	}
+	return true, nil
+}
+
+// process はアーカイブを作成してストレージに保存する
+// 大きなアカウントでもメモリに載せないよう、一時ファイルに書き出してから保存する
+func (u *dataExportUsecase) process(ctx context.Context, export *entity.DataExport) error {
+	user, err := u.userRepo.FindByID(ctx, export.UserID)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp("", "data-export-*.zip")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer func() {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+	}()
+
+	if err := u.WriteArchive(ctx, user, tmp); err != nil {
+		return err
+	}
+	size, err := tmp.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	key := path.Join(DataExportKeyPrefix, user.ID, export.ID+".zip")
+	opts := storage.PutOptions{ContentType: DataExportContentType, CacheControl: storage.CacheControlPrivate}
+	if err := u.store.Put(ctx, key, tmp, size, opts); err != nil {
+		return fmt.Errorf("failed to store archive: %w", err)
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(u.retention)
+	export.Status = entity.DataExportCompleted
+	export.ObjectKey = &key
+	export.SizeBytes = &size
+	export.Error = nil
+	export.CompletedAt = &now
+	export.ExpiresAt = &expiresAt
+	if err := u.exportRepo.UpdateStatus(ctx, export, entity.DataExportProcessing); err != nil {
+		return err
+	}
+
+	slog.InfoContext(ctx, "data export completed", "export_id", export.ID, "user_id", user.ID, "size", size)
+	return nil
+}
+
+func (u *dataExportUsecase) PurgeExpired(ctx context.Context) (int, error) {
+	exports, err := u.exportRepo.ListExpired(ctx, time.Now(), dataExportPurgeBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, export := range exports {
+		if export.ObjectKey != nil {
+			if err := u.store.Delete(ctx, *export.ObjectKey); err != nil && !errors.Is(err, storage.ErrNotFound) {
+				slog.WarnContext(ctx, "failed to delete data export", "export_id", export.ID, "error", err)
+				continue
+			}
+		}
+		export.Status = entity.DataExportExpired
+		export.ObjectKey = nil
+		if err := u.exportRepo.UpdateStatus(ctx, export, entity.DataExportCompleted); err != nil {
+			if errors.Is(err, entity.ErrVersionConflict) {
+				continue
+			}
+			return purged, err
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+func writeZipJSON(zw *zip.Writer, name string, v any) error {
+	w, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func writeZipCSV(zw *zip.Writer, name string, columns []string, rows [][]string) error {
+	w, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	// Excelで文字化けしないようBOMを付ける
+	if _, err := w.Write([]byte("\ufeff")); err != nil {
+		return err
+	}
+	cw := csv.NewWriter(w)
+	if err := cw.Write(columns); err != nil {
+		return err
+	}
+	if err := cw.WriteAll(rows); err != nil {
+		return err
+	}
+	return cw.Error()
+}
+
+func csvString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func csvTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,304 @@
+package usecase
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"seat-management-backend/internal/domain/entity"
+	"seat-management-backend/pkg/storage"
+)
+
+// hookStore はPutの前にbeforePutを呼ぶBlobStore（処理中の割り込みを再現する）
+// beforePutがエラーを返した場合は保存せずにそのエラーを返す
+type hookStore struct {
+	storage.BlobStore
+	beforePut func() error
+}
+
+func (s *hookStore) Put(ctx context.Context, key string, r io.Reader, size int64, opts storage.PutOptions) error {
+	if s.beforePut != nil {
+		if err := s.beforePut(); err != nil {
+			return err
+		}
+	}
+	return s.BlobStore.Put(ctx, key, r, size, opts)
+}
+
+type dataExportFixture struct {
+	user    *entity.User
+	audits  *fakeAuditEventRepository
+	exports *fakeDataExportRepository
+	store   *hookStore
+	usecase DataExportUsecase
+}
+
+func newDataExportFixture(t *testing.T, syncMaxRecords int) *dataExportFixture {
+	t.Helper()
+	local, err := storage.NewLocalStore(t.TempDir(), "http://localhost/uploads")
+	if err != nil {
+		t.Fatalf("NewLocalStore: %v", err)
+	}
+	f := &dataExportFixture{
+		user: &entity.User{
+			ID:                    testID(100),
+			ClerkUserID:           "user_test",
+			Email:                 "taro@example.com",
+			Name:                  "Taro",
+			DefaultPrivacySetting: entity.PrivacyPrivate,
+		},
+		audits:  &fakeAuditEventRepository{},
+		exports: &fakeDataExportRepository{},
+		store:   &hookStore{BlobStore: local},
+	}
+	users := &fakeUserRepository{users: map[string]*entity.User{f.user.ID: f.user}}
+	f.usecase = NewDataExportUsecase(users, f.exports, f.audits, f.store, syncMaxRecords, time.Hour)
+	return f
+}
+
+// seedActivity はユーザーを対象にした監査ログをn件と、他のユーザーを対象にしたものを1件作成する
+func (f *dataExportFixture) seedActivity(n int) {
+	for i := 0; i < n; i++ {
+		_ = f.audits.Create(context.Background(), &entity.AuditEvent{
+			OccurredAt: time.Date(2026, 1, 1, 0, 0, i, 0, time.UTC),
+			ActorType:  "system",
+			Action:     entity.AuditUserUpdated,
+			TargetType: entity.AuditTargetUser,
+			TargetID:   &f.user.ID,
+		})
+	}
+	other := testID(999)
+	_ = f.audits.Create(context.Background(), &entity.AuditEvent{
+		ActorType:  "system",
+		Action:     entity.AuditUserUpdated,
+		TargetType: entity.AuditTargetUser,
+		TargetID:   &other,
+	})
+}
+
+func TestDataExportRequiresAsyncCountsActivity(t *testing.T) {
+	// profileとprivacy_settingsで2件（ログインの記録はない）
+	f := newDataExportFixture(t, 3)
+	f.seedActivity(1)
+	async, err := f.usecase.RequiresAsync(context.Background(), f.user)
+	if err != nil {
+		t.Fatalf("RequiresAsync: %v", err)
+	}
+	if async {
+		t.Error("3 records should be exported synchronously")
+	}
+
+	f.seedActivity(1)
+	async, err = f.usecase.RequiresAsync(context.Background(), f.user)
+	if err != nil {
+		t.Fatalf("RequiresAsync: %v", err)
+	}
+	if !async {
+		t.Error("4 records should require an async export")
+	}
+}
+
+func TestDataExportAsyncPath(t *testing.T) {
+	ctx := context.Background()
+	f := newDataExportFixture(t, 3)
+	f.seedActivity(5)
+
+	async, err := f.usecase.RequiresAsync(ctx, f.user)
+	if err != nil || !async {
+		t.Fatalf("RequiresAsync = %v, %v; want true", async, err)
+	}
+
+	export, err := f.usecase.Request(ctx, f.user)
+	if err != nil {
+		t.Fatalf("Request: %v", err)
+	}
+	if export.Status != entity.DataExportPending {
+		t.Fatalf("status = %s, want pending", export.Status)
+	}
+	if again, err := f.usecase.Request(ctx, f.user); err != nil || again.ID != export.ID {
+		t.Fatalf("second Request = %v, %v; want the pending export %s", again, err, export.ID)
+	}
+
+	if _, _, err := f.usecase.Open(ctx, f.user.ID, export.ID); !errors.Is(err, entity.ErrDataExportNotReady) {
+		t.Fatalf("Open before processing: err = %v, want ErrDataExportNotReady", err)
+	}
+
+	processed, err := f.usecase.ProcessNext(ctx)
+	if err != nil || !processed {
+		t.Fatalf("ProcessNext = %v, %v; want true, nil", processed, err)
+	}
+	if processed, err := f.usecase.ProcessNext(ctx); err != nil || processed {
+		t.Fatalf("second ProcessNext = %v, %v; want false, nil", processed, err)
+	}
+
+	rc, done, err := f.usecase.Open(ctx, f.user.ID, export.ID)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer rc.Close()
+	if done.Status != entity.DataExportCompleted || done.SizeBytes == nil || done.ExpiresAt == nil {
+		t.Fatalf("export = %+v, want completed with size and expiry", done)
+	}
+	if _, _, err := f.usecase.Open(ctx, testID(999), export.ID); !errors.Is(err, entity.ErrDataExportNotFound) {
+		t.Errorf("Open by another user: err = %v, want ErrDataExportNotFound", err)
+	}
+
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatalf("read archive: %v", err)
+	}
+	if int64(len(data)) != *done.SizeBytes {
+		t.Errorf("archive size = %d, want %d", len(data), *done.SizeBytes)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("open zip: %v", err)
+	}
+	files := map[string]*zip.File{}
+	for _, file := range zr.File {
+		files[file.Name] = file
+	}
+
+	var manifest exportManifest
+	readZipJSON(t, files, "manifest.json", &manifest)
+	records := map[string]int{}
+	for _, s := range manifest.Sections {
+		records[s.Name] = s.Records
+		for _, name := range s.Files {
+			if files[name] == nil {
+				t.Errorf("manifest lists %s but the archive does not contain it", name)
+			}
+		}
+	}
+	want := map[string]int{"profile": 1, "login_history": 0, "privacy_settings": 1, "activity": 5}
+	for name, n := range want {
+		if records[name] != n {
+			t.Errorf("manifest records[%s] = %d, want %d", name, records[name], n)
+		}
+	}
+
+	var activities []exportActivity
+	readZipJSON(t, files, "activity.json", &activities)
+	if len(activities) != 5 {
+		t.Fatalf("activity.json has %d entries, want 5", len(activities))
+	}
+	// 新しい順
+	if !activities[0].OccurredAt.After(activities[4].OccurredAt) {
+		t.Errorf("activity.json is not ordered newest first: %v ... %v", activities[0].OccurredAt, activities[4].OccurredAt)
+	}
+
+	var profile exportProfile
+	readZipJSON(t, files, "profile.json", &profile)
+	if profile.ID != f.user.ID || profile.Email != f.user.Email {
+		t.Errorf("profile = %+v, want the user's profile", profile)
+	}
+}
+
+func TestDataExportProcessNextLeavesReclaimedExport(t *testing.T) {
+	ctx := context.Background()
+	f := newDataExportFixture(t, 0)
+	export, err := f.usecase.Request(ctx, f.user)
+	if err != nil {
+		t.Fatalf("Request: %v", err)
+	}
+
+	// アーカイブの作成中に、別のワーカーが処理中のジョブを取り直したことにする
+	reclaimedAt := time.Now().Add(time.Minute)
+	f.store.beforePut = func() error {
+		f.exports.mu.Lock()
+		defer f.exports.mu.Unlock()
+		f.exports.exports[0].StartedAt = &reclaimedAt
+		return nil
+	}
+
+	processed, err := f.usecase.ProcessNext(ctx)
+	if err != nil || !processed {
+		t.Fatalf("ProcessNext = %v, %v; want true, nil", processed, err)
+	}
+	got, err := f.usecase.Get(ctx, f.user.ID, export.ID)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.Status != entity.DataExportProcessing || !got.StartedAt.Equal(reclaimedAt) {
+		t.Errorf("export = %s started at %v, want it left processing for the other worker", got.Status, got.StartedAt)
+	}
+	if got.Error != nil {
+		t.Errorf("error = %q, want the reclaimed export not to be marked failed", *got.Error)
+	}
+}
+
+func TestDataExportProcessNextRecordsFailureAfterCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	f := newDataExportFixture(t, 0)
+	export, err := f.usecase.Request(ctx, f.user)
+	if err != nil {
+		t.Fatalf("Request: %v", err)
+	}
+
+	// アーカイブの保存中にワーカーが停止したことにする
+	f.store.beforePut = func() error {
+		cancel()
+		return ctx.Err()
+	}
+
+	processed, err := f.usecase.ProcessNext(ctx)
+	if !processed || !errors.Is(err, context.Canceled) {
+		t.Fatalf("ProcessNext = %v, %v; want true, context.Canceled", processed, err)
+	}
+	got, err := f.usecase.Get(context.Background(), f.user.ID, export.ID)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.Status != entity.DataExportFailed || got.Error == nil {
+		t.Errorf("export = %s, want it recorded as failed despite the cancelled context", got.Status)
+	}
+}
+
+func TestDataExportPurgeExpired(t *testing.T) {
+	ctx := context.Background()
+	f := newDataExportFixture(t, 0)
+	export, err := f.usecase.Request(ctx, f.user)
+	if err != nil {
+		t.Fatalf("Request: %v", err)
+	}
+	if _, err := f.usecase.ProcessNext(ctx); err != nil {
+		t.Fatalf("ProcessNext: %v", err)
+	}
+
+	// 保存期間を過ぎたことにする
+	expired := time.Now().Add(-time.Minute)
+	f.exports.exports[0].ExpiresAt = &expired
+
+	purged, err := f.usecase.PurgeExpired(ctx)
+	if err != nil || purged != 1 {
+		t.Fatalf("PurgeExpired = %d, %v; want 1, nil", purged, err)
+	}
+	if _, _, err := f.usecase.Open(ctx, f.user.ID, export.ID); !errors.Is(err, entity.ErrDataExportExpired) {
+		t.Errorf("Open after purge: err = %v, want ErrDataExportExpired", err)
+	}
+	if purged, err := f.usecase.PurgeExpired(ctx); err != nil || purged != 0 {
+		t.Errorf("second PurgeExpired = %d, %v; want 0, nil", purged, err)
+	}
+}
+
+func readZipJSON(t *testing.T, files map[string]*zip.File, name string, v any) {
+	t.Helper()
+	file := files[name]
+	if file == nil {
+		t.Fatalf("archive does not contain %s", name)
+	}
+	r, err := file.Open()
+	if err != nil {
+		t.Fatalf("open %s: %v", name, err)
+	}
+	defer r.Close()
+	if err := json.NewDecoder(r).Decode(v); err != nil {
+		t.Fatalf("decode %s: %v", name, err)
+	}
+}
@@ -0,0 +1,288 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"seat-management-backend/internal/domain/entity"
+	"seat-management-backend/internal/domain/repository"
+	"seat-management-backend/pkg/audit"
+	"seat-management-backend/pkg/pagination"
+)
+
+// fakeAuditEventRepository はメモリ上に監査ログを保存するAuditEventRepository
+type fakeAuditEventRepository struct {
+	mu     sync.Mutex
+	events []*entity.AuditEvent
+	// listErrAfter回目以降のListはlistErrを返す（0の場合は常に成功）
+	listErr      error
+	listErrAfter int
+	lists        int
+}
+
+func (r *fakeAuditEventRepository) Create(_ context.Context, event *entity.AuditEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if event.ID == "" {
+		event.ID = testID(len(r.events) + 1)
+	}
+	r.events = append(r.events, event)
+	return nil
+}
+
+func (r *fakeAuditEventRepository) List(_ context.Context, filter repository.AuditEventFilter, cursor string, limit int) (*pagination.Page[*entity.AuditEvent], error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lists++
+	if r.listErr != nil && r.lists > r.listErrAfter {
+		return nil, r.listErr
+	}
+
+	afterID, err := pagination.DecodeCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+	var matched []*entity.AuditEvent
+	for _, e := range r.events {
+		if afterID != "" && e.ID >= afterID {
+			continue
+		}
+		if auditEventMatches(e, filter) {
+			matched = append(matched, e)
+		}
+	}
+	slices.SortFunc(matched, func(a, b *entity.AuditEvent) int { return strings.Compare(b.ID, a.ID) })
+	if len(matched) > limit+1 {
+		matched = matched[:limit+1]
+	}
+	return pagination.NewPage(matched, limit, func(e *entity.AuditEvent) string { return e.ID }), nil
+}
+
+func (r *fakeAuditEventRepository) Count(_ context.Context, filter repository.AuditEventFilter) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var n int64
+	for _, e := range r.events {
+		if auditEventMatches(e, filter) {
+			n++
+		}
+	}
+	return n, nil
+}
+
+// auditEventMatches はfilterのうちテストで使う条件（対象）だけを判定する
+func auditEventMatches(e *entity.AuditEvent, filter repository.AuditEventFilter) bool {
+	if filter.TargetType != "" && e.TargetType != filter.TargetType {
+		return false
+	}
+	if filter.TargetID != "" && (e.TargetID == nil || *e.TargetID != filter.TargetID) {
+		return false
+	}
+	return true
+}
+
+// testID は並び順がnと一致するULIDを返す
+func testID(n int) string {
+	return fmt.Sprintf("01J%023d", n)
+}
+
+func (r *fakeAuditEventRepository) ScrubActor(_ context.Context, actorID, replacement string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, e := range r.events {
+		if e.ActorType == audit.ActorUser && e.ActorID != nil && *e.ActorID == actorID {
+			e.ActorID = &replacement
+			e.IPAddress = nil
+			e.SessionID = nil
+		}
+	}
+	return nil
+}
+
+// fakeUserRepository はメモリ上のユーザーを返すUserRepository
+// テストで使わないメソッドは埋め込んだnilのインターフェースに委ねる（呼ぶとpanicする）
+type fakeUserRepository struct {
+	repository.UserRepository
+	users map[string]*entity.User
+}
+
+func (r *fakeUserRepository) FindByID(_ context.Context, id string) (*entity.User, error) {
+	user, ok := r.users[id]
+	if !ok {
+		return nil, entity.ErrUserNotFound
+	}
+	return user, nil
+}
+
+func (r *fakeUserRepository) FindByClerkUserID(_ context.Context, clerkUserID string) (*entity.User, error) {
+	for _, user := range r.users {
+		if user.ClerkUserID == clerkUserID {
+			found := *user
+			return &found, nil
+		}
+	}
+	return nil, entity.ErrUserNotFound
+}
+
+func (r *fakeUserRepository) Create(_ context.Context, user *entity.User) error {
+	if user.ID == "" {
+		user.ID = testID(len(r.users) + 1)
+	}
+	stored := *user
+	r.users[user.ID] = &stored
+	return nil
+}
+
+// Update はfieldsで指定した項目だけを保存する（バージョンは確認しない）
+func (r *fakeUserRepository) Update(_ context.Context, user *entity.User, fields ...string) error {
+	stored, ok := r.users[user.ID]
+	if !ok {
+		return entity.ErrUserNotFound
+	}
+	for _, field := range fields {
+		switch field {
+		case entity.UserColumnEmail:
+			stored.Email = user.Email
+		case entity.UserColumnName:
+			stored.Name = user.Name
+		case entity.UserColumnAvatarURL:
+			stored.AvatarURL = user.AvatarURL
+		case entity.UserColumnPrimaryAuthProvider:
+			stored.PrimaryAuthProvider = user.PrimaryAuthProvider
+		default:
+			return fmt.Errorf("fakeUserRepository: unsupported field %s", field)
+		}
+	}
+	stored.Version++
+	user.Version = stored.Version
+	return nil
+}
+
+// fakeUserDirectory は決まったユーザーを返すUserDirectory
+type fakeUserDirectory struct {
+	users []*entity.User
+}
+
+func (d *fakeUserDirectory) ListUsers(_ context.Context, offset, limit int) ([]*entity.User, error) {
+	if offset >= len(d.users) {
+		return nil, nil
+	}
+	return d.users[offset:min(offset+limit, len(d.users))], nil
+}
+
+// fakeDataExportRepository はメモリ上にエクスポートを保存するDataExportRepository
+type fakeDataExportRepository struct {
+	mu      sync.Mutex
+	exports []*entity.DataExport
+}
+
+func (r *fakeDataExportRepository) Create(_ context.Context, export *entity.DataExport) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if export.ID == "" {
+		export.ID = testID(len(r.exports) + 1)
+	}
+	stored := *export
+	r.exports = append(r.exports, &stored)
+	return nil
+}
+
+func (r *fakeDataExportRepository) FindByID(_ context.Context, userID, id string) (*entity.DataExport, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, e := range r.exports {
+		if e.ID == id && e.UserID == userID {
+			found := *e
+			return &found, nil
+		}
+	}
+	return nil, entity.ErrDataExportNotFound
+}
+
+func (r *fakeDataExportRepository) FindActiveByUserID(_ context.Context, userID string) (*entity.DataExport, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, e := range r.exports {
+		if e.UserID == userID && !e.Status.IsDone() {
+			found := *e
+			return &found, nil
+		}
+	}
+	return nil, entity.ErrDataExportNotFound
+}
+
+func (r *fakeDataExportRepository) ClaimNext(_ context.Context, staleAfter time.Duration) (*entity.DataExport, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	for _, e := range r.exports {
+		stale := e.Status == entity.DataExportProcessing && e.StartedAt != nil && e.StartedAt.Before(now.Add(-staleAfter))
+		if e.Status == entity.DataExportPending || stale {
+			e.Status = entity.DataExportProcessing
+			e.StartedAt = &now
+			claimed := *e
+			return &claimed, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeDataExportRepository) UpdateStatus(ctx context.Context, export *entity.DataExport, from entity.DataExportStatus) error {
+	// DBと同じく、取り消されたctxでは保存できない
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, e := range r.exports {
+		if e.ID != export.ID {
+			continue
+		}
+		sameStart := (e.StartedAt == nil) == (export.StartedAt == nil) &&
+			(e.StartedAt == nil || e.StartedAt.Equal(*export.StartedAt))
+		if e.Status != from || !sameStart {
+			return entity.ErrVersionConflict
+		}
+		stored := *export
+		r.exports[i] = &stored
+		return nil
+	}
+	return entity.ErrVersionConflict
+}
+
+func (r *fakeDataExportRepository) ListExpired(_ context.Context, now time.Time, limit int) ([]*entity.DataExport, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var expired []*entity.DataExport
+	for _, e := range r.exports {
+		if e.Status == entity.DataExportCompleted && e.ExpiresAt != nil && e.ExpiresAt.Before(now) && len(expired) < limit {
+			found := *e
+			expired = append(expired, &found)
+		}
+	}
+	return expired, nil
+}
+
+func (r *fakeDataExportRepository) ListByUserID(_ context.Context, userID string) ([]*entity.DataExport, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var found []*entity.DataExport
+	for _, e := range r.exports {
+		if e.UserID == userID {
+			c := *e
+			found = append(found, &c)
+		}
+	}
+	return found, nil
+}
+
+func (r *fakeDataExportRepository) DeleteByUserID(_ context.Context, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.exports = slices.DeleteFunc(r.exports, func(e *entity.DataExport) bool { return e.UserID == userID })
+	return nil
+}
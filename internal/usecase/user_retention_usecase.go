@@ -0,0 +1,122 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"seat-management-backend/internal/domain/entity"
+	"seat-management-backend/internal/domain/repository"
+	"seat-management-backend/internal/metrics"
+	"seat-management-backend/pkg/storage"
+	"seat-management-backend/pkg/tracing"
+)
+
+// userAnonymizeBatchSize は一度に取得して処理するユーザー数
+const userAnonymizeBatchSize = 100
+
+// UserRetentionResult は個人情報の消去の結果
+type UserRetentionResult struct {
+	Anonymized int
+	Failed     int
+}
+
+// UserRetentionUsecase は削除済みユーザーの保存期間の管理を定義
+type UserRetentionUsecase interface {
+	// AnonymizeDeleted は保存期間を過ぎた削除済みユーザーの個人情報を消去する
+	AnonymizeDeleted(ctx context.Context) (*UserRetentionResult, error)
+}
+
+type userRetentionUsecase struct {
+	userRepo   repository.UserRepository
+	exportRepo repository.DataExportRepository
+	audit      AuditUsecase
+	store      storage.BlobStore
+	metrics    *metrics.Metrics
+	retention  time.Duration
+}
+
+// NewUserRetentionUsecase はUserRetentionUsecaseの新しいインスタンスを作成
+// 削除からretentionが過ぎたユーザーが対象になる
+func NewUserRetentionUsecase(ur repository.UserRepository, er repository.DataExportRepository, au AuditUsecase, store storage.BlobStore, m *metrics.Metrics, retention time.Duration) UserRetentionUsecase {
+	return &userRetentionUsecase{
+		userRepo:   ur,
+		exportRepo: er,
+		audit:      au,
+		store:      store,
+		metrics:    m,
+		retention:  retention,
+	}
+}
+
+// AnonymizeDeleted は対象のユーザーがなくなるまでバッチで処理する
+// 失敗したユーザーは次回の実行で再び対象になる
+func (u *userRetentionUsecase) AnonymizeDeleted(ctx context.Context) (result *UserRetentionResult, err error) {
+	ctx, span := tracing.Start(ctx, "UserRetentionUsecase.AnonymizeDeleted")
+	defer func() { tracing.End(span, err) }()
+
+	result = &UserRetentionResult{}
+	before := time.Now().Add(-u.retention)
+	for ctx.Err() == nil {
+		users, err := u.userRepo.ListPendingAnonymization(ctx, before, userAnonymizeBatchSize)
+		if err != nil {
+			return result, err
+		}
+
+		for _, user := range users {
+			if err := u.anonymize(ctx, user); err != nil {
+				slog.WarnContext(ctx, "retention: failed to anonymize user", "user_id", user.ID, "error", err)
+				result.Failed++
+				continue
+			}
+			result.Anonymized++
+		}
+
+		// 失敗したユーザーが残り続けても終わるよう、満杯でないバッチで止める
+		if len(users) < userAnonymizeBatchSize || result.Failed > 0 {
+			break
+		}
+	}
+	return result, nil
+}
+
+// anonymize はユーザーに紐づく個人データを削除し、ユーザー自身の個人情報を消去する
+// 予約など集計に使うデータはユーザーの行（墓標）に紐づいたまま残す
+func (u *userRetentionUsecase) anonymize(ctx context.Context, user *entity.User) error {
+	exports, err := u.exportRepo.ListByUserID(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	for _, export := range exports {
+		if export.ObjectKey == nil {
+			continue
+		}
+		if err := u.store.Delete(ctx, *export.ObjectKey); err != nil {
+			return err
+		}
+	}
+	if err := u.exportRepo.DeleteByUserID(ctx, user.ID); err != nil {
+		return err
+	}
+
+	if user.AvatarURL != nil {
+		deleteAvatarObjects(ctx, u.store, user.ID, *user.AvatarURL)
+	}
+
+	// 監査ログの操作者のClerk User IDも墓標と同じIDに置き換える
+	// 消去に失敗したユーザーは次回やり直すため、ユーザーより先に置き換える
+	clerkUserID := user.ClerkUserID
+	user.Anonymize(time.Now())
+	if err := u.audit.ScrubActor(ctx, clerkUserID, user.ClerkUserID); err != nil {
+		return err
+	}
+	if err := u.userRepo.Anonymize(ctx, user); err != nil {
+		return err
+	}
+
+	// 消去した個人情報を監査ログに残さないよう、変更内容は記録しない
+	u.audit.Record(ctx, entity.AuditUserAnonymized, entity.AuditTargetUser, user.ID, nil)
+	u.metrics.UserEvent("anonymized")
+	slog.InfoContext(ctx, "retention: user anonymized", "user_id", user.ID)
+	return nil
+}
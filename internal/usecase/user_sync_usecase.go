@@ -0,0 +1,135 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+
+	"seat-management-backend/internal/domain/entity"
+	"seat-management-backend/internal/domain/repository"
+	"seat-management-backend/internal/metrics"
+	"seat-management-backend/pkg/storage"
+	"seat-management-backend/pkg/tracing"
+)
+
+const userSyncPageSize = 100
+
+// userSyncColumns は外部認証基盤の値で上書きするカラム
+// アバターはアップロードされた画像でない場合だけ上書きする（upsertを参照）
+var userSyncColumns = []string{
+	entity.UserColumnEmail,
+	entity.UserColumnName,
+	entity.UserColumnPrimaryAuthProvider,
+}
+
+// UserSyncResult は同期結果の件数
+type UserSyncResult struct {
+	Created int `json:"created"`
+	Updated int `json:"updated"`
+	Skipped int `json:"skipped"`
+	Failed  int `json:"failed"`
+}
+
+// UserSyncUsecase は外部認証基盤とusersテーブルの同期を定義
+// Webhookの取りこぼしを補正するために使う
+type UserSyncUsecase interface {
+	SyncAll(ctx context.Context) (*UserSyncResult, error)
+}
+
+type userSyncUsecase struct {
+	directory repository.UserDirectory
+	userRepo  repository.UserRepository
+	audit     AuditUsecase
+	store     storage.BlobStore
+	metrics   *metrics.Metrics
+}
+
+// NewUserSyncUsecase はUserSyncUsecaseの新しいインスタンスを作成
+// storeはアップロードされたアバター画像を見分けるために使う
+func NewUserSyncUsecase(dir repository.UserDirectory, ur repository.UserRepository, au AuditUsecase, store storage.BlobStore, m *metrics.Metrics) UserSyncUsecase {
+	return &userSyncUsecase{
+		directory: dir,
+		userRepo:  ur,
+		audit:     au,
+		store:     store,
+		metrics:   m,
+	}
+}
+
+// SyncAll は外部認証基盤の全ユーザーをusersテーブルに反映する
+// 個々のユーザーの失敗は件数として記録し、同期は継続する
+func (u *userSyncUsecase) SyncAll(ctx context.Context) (result *UserSyncResult, err error) {
+	ctx, span := tracing.Start(ctx, "UserSyncUsecase.SyncAll")
+	defer func() { tracing.End(span, err) }()
+
+	result = &UserSyncResult{}
+	defer func() {
+		u.metrics.UserSynced("created", result.Created)
+		u.metrics.UserSynced("updated", result.Updated)
+		u.metrics.UserSynced("skipped", result.Skipped)
+		u.metrics.UserSynced("failed", result.Failed)
+	}()
+
+	for offset := 0; ; offset += userSyncPageSize {
+		users, err := u.directory.ListUsers(ctx, offset, userSyncPageSize)
+		if err != nil {
+			return result, err
+		}
+
+		for _, user := range users {
+			// メールアドレスのないユーザー（テストユーザーなど）は取り込まない
+			if user.Email == "" {
+				result.Skipped++
+				continue
+			}
+
+			created, err := u.upsert(ctx, user)
+			switch {
+			case err != nil:
+				slog.WarnContext(ctx, "sync: failed to sync user", "clerk_user_id", user.ClerkUserID, "error", err)
+				result.Failed++
+			case created:
+				result.Created++
+			default:
+				result.Updated++
+			}
+		}
+
+		if len(users) < userSyncPageSize {
+			return result, nil
+		}
+	}
+}
+
+func (u *userSyncUsecase) upsert(ctx context.Context, src *entity.User) (bool, error) {
+	existing, err := u.userRepo.FindByClerkUserID(ctx, src.ClerkUserID)
+	if errors.Is(err, entity.ErrUserNotFound) {
+		if err := u.userRepo.Create(ctx, src); err != nil {
+			return true, err
+		}
+		u.audit.Record(ctx, entity.AuditUserCreated, entity.AuditTargetUser, src.ID, entity.UserAuditChanges(nil, src))
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	before := *existing
+	existing.Email = src.Email
+	existing.Name = src.Name
+	existing.PrimaryAuthProvider = src.PrimaryAuthProvider
+	fields := slices.Clone(userSyncColumns)
+	if existing.AvatarURL == nil || !isUploadedAvatar(u.store, existing.ID, *existing.AvatarURL) {
+		existing.AvatarURL = src.AvatarURL
+		fields = append(fields, entity.UserColumnAvatarURL)
+	}
+	// 競合した場合は失敗として数え、次回の同期で反映する
+	if err := u.userRepo.Update(ctx, existing, fields...); err != nil {
+		return false, err
+	}
+	if changes := auditUserDiff(&before, existing, fields); len(changes) > 0 {
+		u.audit.Record(ctx, entity.AuditUserUpdated, entity.AuditTargetUser, existing.ID, changes)
+	}
+	return false, nil
+}
@@ -0,0 +1,51 @@
+package usecase
+
+import (
+	"context"
+	"path"
+	"testing"
+
+	"seat-management-backend/internal/domain/entity"
+	"seat-management-backend/pkg/storage"
+)
+
+func TestUserSyncKeepsUploadedAvatar(t *testing.T) {
+	store, err := storage.NewLocalStore(t.TempDir(), "http://localhost/uploads")
+	if err != nil {
+		t.Fatalf("NewLocalStore: %v", err)
+	}
+	uploadedURL := store.URL(path.Join(AvatarKeyPrefix, testID(1), testID(9), "256.jpg"))
+	oldClerkURL := "https://img.clerk.com/old"
+	users := &fakeUserRepository{users: map[string]*entity.User{
+		testID(1): {ID: testID(1), ClerkUserID: "user_uploaded", Email: "a@example.com", Name: "A", AvatarURL: &uploadedURL},
+		testID(2): {ID: testID(2), ClerkUserID: "user_clerk", Email: "b@example.com", Name: "B", AvatarURL: &oldClerkURL},
+	}}
+
+	newClerkURL := "https://img.clerk.com/new"
+	dir := &fakeUserDirectory{users: []*entity.User{
+		{ClerkUserID: "user_uploaded", Email: "a@example.com", Name: "A (renamed)", AvatarURL: &newClerkURL},
+		{ClerkUserID: "user_clerk", Email: "b@example.com", Name: "B", AvatarURL: &newClerkURL},
+	}}
+
+	sync := NewUserSyncUsecase(dir, users, NewAuditUsecase(&fakeAuditEventRepository{}), store, nil)
+	result, err := sync.SyncAll(context.Background())
+	if err != nil {
+		t.Fatalf("SyncAll: %v", err)
+	}
+	if result.Updated != 2 || result.Failed != 0 {
+		t.Fatalf("result = %+v, want 2 updated", result)
+	}
+
+	uploaded := users.users[testID(1)]
+	if uploaded.AvatarURL == nil || *uploaded.AvatarURL != uploadedURL {
+		t.Errorf("uploaded avatar = %v, want it kept as %s", uploaded.AvatarURL, uploadedURL)
+	}
+	if uploaded.Name != "A (renamed)" {
+		t.Errorf("name = %q, want the other Clerk fields still synced", uploaded.Name)
+	}
+
+	clerk := users.users[testID(2)]
+	if clerk.AvatarURL == nil || *clerk.AvatarURL != newClerkURL {
+		t.Errorf("Clerk avatar = %v, want it replaced with %s", clerk.AvatarURL, newClerkURL)
+	}
+}
@@ -2,9 +2,14 @@ package usecase
 
 import (
 	"context"
+	"time"
 
 	"seat-management-backend/internal/domain/entity"
 	"seat-management-backend/internal/domain/repository"
+	"seat-management-backend/internal/metrics"
+	"seat-management-backend/pkg/audit"
+	"seat-management-backend/pkg/i18n"
+	"seat-management-backend/pkg/pagination"
 )
 
 // UserUsecase はユーザー関連のビジネスロジックを定義
@@ -13,21 +18,32 @@ type UserUsecase interface {
 	GetByID(ctx context.Context, id string) (*entity.User, error)
 	GetByClerkUserID(ctx context.Context, clerkUserID string) (*entity.User, error)
 	GetByEmail(ctx context.Context, email string) (*entity.User, error)
-	Update(ctx context.Context, user *entity.User) error
+	Update(ctx context.Context, user *entity.User, fields ...string) error
 	UpdateLastLogin(ctx context.Context, userID string) error
 	Delete(ctx context.Context, id string) error
-	List(ctx context.Context, limit, offset int) ([]*entity.User, error)
+	List(ctx context.Context, filter repository.UserFilter, cursor string, limit int) (*pagination.Page[*entity.User], error)
+	Suspend(ctx context.Context, id string) error
+	Unsuspend(ctx context.Context, id string) error
+	Restore(ctx context.Context, id string) error
 }
 
 // userUsecase はUserUsecaseの実装
 type userUsecase struct {
 	userRepo repository.UserRepository
+	audit    AuditUsecase
+	metrics  *metrics.Metrics
 }
 
 // NewUserUsecase はUserUsecaseの新しいインスタンスを作成
-func NewUserUsecase(ur repository.UserRepository) UserUsecase {
-	return &userUsecase{
-		userRepo: ur,
+// 各メソッドの呼び出しはトレーシングのスパンとして記録される
+// 作成・更新・削除などの操作は監査ログに記録される
+func NewUserUsecase(ur repository.UserRepository, au AuditUsecase, m *metrics.Metrics) UserUsecase {
+	return &tracedUserUsecase{
+		next: &userUsecase{
+			userRepo: ur,
+			audit:    au,
+			metrics:  m,
+		},
 	}
 }
 
@@ -41,7 +57,12 @@ func (u *userUsecase) Create(ctx context.Context, user *entity.User) error {
 		user.PrimaryAuthProvider = entity.AuthProviderUnknown
 	}
 
-	return u.userRepo.Create(ctx, user)
+	if err := u.userRepo.Create(ctx, user); err != nil {
+		return err
+	}
+	u.audit.Record(ctx, entity.AuditUserCreated, entity.AuditTargetUser, user.ID, entity.UserAuditChanges(nil, user))
+	u.metrics.UserEvent("created")
+	return nil
 }
 
 // GetByID はIDでユーザーを取得
@@ -59,8 +80,9 @@ func (u *userUsecase) GetByEmail(ctx context.Context, email string) (*entity.Use
 	return u.userRepo.FindByEmail(ctx, email)
 }
 
-// Update はユーザー情報を更新
-func (u *userUsecase) Update(ctx context.Context, user *entity.User) error {
+// Update はユーザー情報のうちfieldsで指定したカラムを更新
+// user.Versionが最新でない場合はErrVersionConflictを返す
+func (u *userUsecase) Update(ctx context.Context, user *entity.User, fields ...string) error {
 	// ビジネスロジック: バリデーションなど
 	if user.Email == "" {
 		return entity.ErrInvalidEmail
@@ -68,8 +90,41 @@ func (u *userUsecase) Update(ctx context.Context, user *entity.User) error {
 	if user.Name == "" {
 		return entity.ErrInvalidName
 	}
+	if !user.DefaultPrivacySetting.IsValid() {
+		return entity.ErrInvalidPrivacySetting
+	}
+	if user.Locale != nil {
+		if _, ok := i18n.ParseLocale(*user.Locale); !ok {
+			return entity.ErrInvalidLocale
+		}
+	}
 
-	return u.userRepo.Update(ctx, user)
+	// 監査ログに変更前の値を残すため、更新前の状態を取得する
+	before, err := u.userRepo.FindByID(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	if err := u.userRepo.Update(ctx, user, fields...); err != nil {
+		return err
+	}
+
+	if changes := auditUserDiff(before, user, fields); len(changes) > 0 {
+		u.audit.Record(ctx, entity.AuditUserUpdated, entity.AuditTargetUser, user.ID, changes)
+	}
+	return nil
+}
+
+// auditUserDiff はfieldsで指定したカラムのうち値が変わったものを返す
+// 保存していないカラムの差分は含めない（fieldsが空なら変更なし）
+func auditUserDiff(before, after *entity.User, fields []string) audit.Changes {
+	changes := entity.UserAuditChanges(before, after)
+	selected := audit.Changes{}
+	for _, field := range fields {
+		if change, ok := changes[field]; ok {
+			selected[field] = change
+		}
+	}
+	return selected
 }
 
 // UpdateLastLogin は最終ログイン時刻を更新
@@ -79,18 +134,73 @@ func (u *userUsecase) UpdateLastLogin(ctx context.Context, userID string) error
 
 // Delete はユーザーを削除（ソフトデリート）
 func (u *userUsecase) Delete(ctx context.Context, id string) error {
-	return u.userRepo.Delete(ctx, id)
+	if err := u.userRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+	u.audit.Record(ctx, entity.AuditUserDeleted, entity.AuditTargetUser, id, nil)
+	u.metrics.UserEvent("deleted")
+	return nil
 }
 
 // List はユーザー一覧を取得
-func (u *userUsecase) List(ctx context.Context, limit, offset int) ([]*entity.User, error) {
+func (u *userUsecase) List(ctx context.Context, filter repository.UserFilter, cursor string, limit int) (*pagination.Page[*entity.User], error) {
 	// ビジネスロジック: limitの最大値チェックなど
-	if limit <= 0 || limit > 100 {
-		limit = 20 // デフォルト値
+	limit = pagination.NormalizeLimit(limit)
+
+	if filter.AuthProvider != "" && !filter.AuthProvider.IsValid() {
+		return nil, entity.ErrInvalidAuthProvider
+	}
+
+	return u.userRepo.List(ctx, filter, cursor, limit)
+}
+
+// Suspend はユーザーを利用停止にする
+func (u *userUsecase) Suspend(ctx context.Context, id string) error {
+	user, err := u.userRepo.FindByID(ctx, id)
+	if err != nil {
+		return err
 	}
-	if offset < 0 {
-		offset = 0
+	if user.IsSuspended() {
+		return nil
 	}
 
-	return u.userRepo.List(ctx, limit, offset)
+	now := time.Now()
+	if err := u.userRepo.UpdateSuspendedAt(ctx, id, &now); err != nil {
+		return err
+	}
+	suspended := *user
+	suspended.SuspendedAt = &now
+	u.audit.Record(ctx, entity.AuditUserSuspended, entity.AuditTargetUser, id, entity.UserAuditChanges(user, &suspended))
+	u.metrics.UserEvent("suspended")
+	return nil
+}
+
+// Unsuspend はユーザーの利用停止を解除する
+func (u *userUsecase) Unsuspend(ctx context.Context, id string) error {
+	user, err := u.userRepo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !user.IsSuspended() {
+		return nil
+	}
+
+	if err := u.userRepo.UpdateSuspendedAt(ctx, id, nil); err != nil {
+		return err
+	}
+	unsuspended := *user
+	unsuspended.SuspendedAt = nil
+	u.audit.Record(ctx, entity.AuditUserUnsuspended, entity.AuditTargetUser, id, entity.UserAuditChanges(user, &unsuspended))
+	u.metrics.UserEvent("unsuspended")
+	return nil
+}
+
+// Restore はソフトデリートされたユーザーを復元する
+func (u *userUsecase) Restore(ctx context.Context, id string) error {
+	if err := u.userRepo.Restore(ctx, id); err != nil {
+		return err
+	}
+	u.audit.Record(ctx, entity.AuditUserRestored, entity.AuditTargetUser, id, nil)
+	u.metrics.UserEvent("restored")
+	return nil
 }
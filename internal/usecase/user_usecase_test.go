@@ -0,0 +1,31 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"seat-management-backend/internal/domain/entity"
+)
+
+func TestUserUpdateWithoutFieldsBumpsVersionOnly(t *testing.T) {
+	stored := &entity.User{ID: testID(1), Email: "a@example.com", Name: "A", DefaultPrivacySetting: entity.PrivacyPrivate, Version: 1}
+	users := &fakeUserRepository{users: map[string]*entity.User{stored.ID: stored}}
+	audits := &fakeAuditEventRepository{}
+	uu := NewUserUsecase(users, NewAuditUsecase(audits), nil)
+
+	// 保存しない項目を変えても記録されない
+	user := *stored
+	user.Name = "Not saved"
+	if err := uu.Update(context.Background(), &user); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if user.Version != 2 {
+		t.Errorf("version = %d, want 2", user.Version)
+	}
+	if stored.Name != "A" {
+		t.Errorf("stored name = %q, want it unchanged", stored.Name)
+	}
+	if len(audits.events) != 0 {
+		t.Errorf("recorded %d audit events, want none for an update without fields", len(audits.events))
+	}
+}
@@ -0,0 +1,81 @@
+package usecase
+
+import (
+	"context"
+
+	"seat-management-backend/internal/domain/entity"
+	"seat-management-backend/internal/domain/repository"
+	"seat-management-backend/pkg/pagination"
+	"seat-management-backend/pkg/tracing"
+)
+
+// tracedUserUsecase はUserUsecaseの各メソッドをスパンで囲むデコレーター
+type tracedUserUsecase struct {
+	next UserUsecase
+}
+
+func (t *tracedUserUsecase) Create(ctx context.Context, user *entity.User) (err error) {
+	ctx, span := tracing.Start(ctx, "UserUsecase.Create")
+	defer func() { tracing.End(span, err) }()
+	return t.next.Create(ctx, user)
+}
+
+func (t *tracedUserUsecase) GetByID(ctx context.Context, id string) (_ *entity.User, err error) {
+	ctx, span := tracing.Start(ctx, "UserUsecase.GetByID")
+	defer func() { tracing.End(span, err) }()
+	return t.next.GetByID(ctx, id)
+}
+
+func (t *tracedUserUsecase) GetByClerkUserID(ctx context.Context, clerkUserID string) (_ *entity.User, err error) {
+	ctx, span := tracing.Start(ctx, "UserUsecase.GetByClerkUserID")
+	defer func() { tracing.End(span, err) }()
+	return t.next.GetByClerkUserID(ctx, clerkUserID)
+}
+
+func (t *tracedUserUsecase) GetByEmail(ctx context.Context, email string) (_ *entity.User, err error) {
+	ctx, span := tracing.Start(ctx, "UserUsecase.GetByEmail")
+	defer func() { tracing.End(span, err) }()
+	return t.next.GetByEmail(ctx, email)
+}
+
+func (t *tracedUserUsecase) Update(ctx context.Context, user *entity.User, fields ...string) (err error) {
+	ctx, span := tracing.Start(ctx, "UserUsecase.Update")
+	defer func() { tracing.End(span, err) }()
+	return t.next.Update(ctx, user, fields...)
+}
+
+func (t *tracedUserUsecase) UpdateLastLogin(ctx context.Context, userID string) (err error) {
+	ctx, span := tracing.Start(ctx, "UserUsecase.UpdateLastLogin")
+	defer func() { tracing.End(span, err) }()
+	return t.next.UpdateLastLogin(ctx, userID)
+}
+
+func (t *tracedUserUsecase) Delete(ctx context.Context, id string) (err error) {
+	ctx, span := tracing.Start(ctx, "UserUsecase.Delete")
+	defer func() { tracing.End(span, err) }()
+	return t.next.Delete(ctx, id)
+}
+
+func (t *tracedUserUsecase) List(ctx context.Context, filter repository.UserFilter, cursor string, limit int) (_ *pagination.Page[*entity.User], err error) {
+	ctx, span := tracing.Start(ctx, "UserUsecase.List")
+	defer func() { tracing.End(span, err) }()
+	return t.next.List(ctx, filter, cursor, limit)
+}
+
+func (t *tracedUserUsecase) Suspend(ctx context.Context, id string) (err error) {
+	ctx, span := tracing.Start(ctx, "UserUsecase.Suspend")
+	defer func() { tracing.End(span, err) }()
+	return t.next.Suspend(ctx, id)
+}
+
+func (t *tracedUserUsecase) Unsuspend(ctx context.Context, id string) (err error) {
+	ctx, span := tracing.Start(ctx, "UserUsecase.Unsuspend")
+	defer func() { tracing.End(span, err) }()
+	return t.next.Unsuspend(ctx, id)
+}
+
+func (t *tracedUserUsecase) Restore(ctx context.Context, id string) (err error) {
+	ctx, span := tracing.Start(ctx, "UserUsecase.Restore")
+	defer func() { tracing.End(span, err) }()
+	return t.next.Restore(ctx, id)
+}
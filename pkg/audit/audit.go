@@ -0,0 +1,85 @@
+package audit
+
+import (
+	"context"
+	"reflect"
+)
+
+// ActorType は操作を行った主体の種類
+type ActorType string
+
+const (
+	// ActorUser はAPIを呼び出したユーザー（管理者を含む）
+	ActorUser ActorType = "user"
+	// ActorWebhook は外部サービスからのWebhook
+	ActorWebhook ActorType = "webhook"
+	// ActorSystem はワーカーやコマンドなどのシステム処理
+	ActorSystem ActorType = "system"
+)
+
+// Actor は監査ログに記録する操作者の情報
+type Actor struct {
+	Type ActorType
+	// ユーザーの場合はClerk User ID（個人情報の消去後は墓標のID）、Webhookの場合は送信元、システムの場合はジョブ名など
+	ID        string
+	Role      string
+	IP        string
+	SessionID string
+	RequestID string
+}
+
+type actorKey struct{}
+
+// WithActor は操作者をコンテキストに設定する
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext はコンテキストの操作者を返す
+// 設定されていない場合はシステムによる操作とみなす
+func ActorFromContext(ctx context.Context) Actor {
+	if actor, ok := ctx.Value(actorKey{}).(Actor); ok {
+		return actor
+	}
+	return Actor{Type: ActorSystem}
+}
+
+// Change は1つの項目の変更前後の値
+// Redactedの場合は値を記録せず、変更されたことだけを表す
+type Change struct {
+	Before   any  `json:"before"`
+	After    any  `json:"after"`
+	Redacted bool `json:"redacted,omitempty"`
+}
+
+// Changes は項目名ごとの変更内容
+type Changes map[string]Change
+
+// Diff は変更前後のスナップショットを比べ、値が異なる項目だけを返す
+// 作成時はbefore、削除時はafterにnilを渡す
+func Diff(before, after map[string]any) Changes {
+	changes := Changes{}
+	for key, a := range after {
+		b := before[key]
+		if !reflect.DeepEqual(b, a) {
+			changes[key] = Change{Before: b, After: a}
+		}
+	}
+	for key, b := range before {
+		if _, ok := after[key]; !ok && b != nil {
+			changes[key] = Change{Before: b, After: nil}
+		}
+	}
+	return changes
+}
+
+// Redact はfieldsの変更前後の値を消し、変更されたことだけを残す
+// 監査ログは後から消去できないため、個人情報の値はこれで伏せてから記録する
+func (c Changes) Redact(fields ...string) Changes {
+	for _, field := range fields {
+		if _, ok := c[field]; ok {
+			c[field] = Change{Redacted: true}
+		}
+	}
+	return c
+}
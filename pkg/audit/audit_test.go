@@ -0,0 +1,65 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	before := map[string]any{"name": "Alice", "locale": "ja", "avatar_url": "https://example.com/a.jpg"}
+	after := map[string]any{"name": "Alice", "locale": "en", "avatar_url": nil}
+
+	changes := Diff(before, after)
+	want := Changes{
+		"locale":     {Before: "ja", After: "en"},
+		"avatar_url": {Before: "https://example.com/a.jpg", After: nil},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("Diff() = %v, want %v", changes, want)
+	}
+	for key, w := range want {
+		if changes[key] != w {
+			t.Errorf("Diff()[%s] = %+v, want %+v", key, changes[key], w)
+		}
+	}
+
+	created := Diff(nil, map[string]any{"name": "Bob", "locale": nil})
+	if len(created) != 1 || created["name"] != (Change{Before: nil, After: "Bob"}) {
+		t.Errorf("Diff(nil, ...) = %v, want only the non-nil fields", created)
+	}
+}
+
+func TestRedact(t *testing.T) {
+	changes := Changes{
+		"email":  {Before: "old@example.com", After: "new@example.com"},
+		"locale": {Before: "ja", After: "en"},
+	}.Redact("email", "name")
+
+	if _, ok := changes["name"]; ok {
+		t.Error("Redact() should not add fields that did not change")
+	}
+	if changes["locale"] != (Change{Before: "ja", After: "en"}) {
+		t.Errorf("Redact() touched a field it was not asked to: %+v", changes["locale"])
+	}
+
+	b, err := json.Marshal(changes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"email":{"before":null,"after":null,"redacted":true},"locale":{"before":"ja","after":"en"}}`
+	if string(b) != want {
+		t.Errorf("json = %s, want %s", b, want)
+	}
+}
+
+func TestActorFromContext(t *testing.T) {
+	if got := ActorFromContext(context.Background()); got.Type != ActorSystem {
+		t.Errorf("ActorFromContext() without an actor = %+v, want system", got)
+	}
+
+	actor := Actor{Type: ActorUser, ID: "user_123", IP: "192.0.2.1"}
+	if got := ActorFromContext(WithActor(context.Background(), actor)); got != actor {
+		t.Errorf("ActorFromContext() = %+v, want %+v", got, actor)
+	}
+}
@@ -0,0 +1,421 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-yaml"
+	"github.com/joho/godotenv"
+)
+
+// Config はアプリケーション全体の設定
+// 優先順位: 環境変数（.envを含む） > YAMLファイル > デフォルト値
+type Config struct {
+	Server    ServerConfig    `yaml:"server" json:"server"`
+	Database  DatabaseConfig  `yaml:"database" json:"database"`
+	Clerk     ClerkConfig     `yaml:"clerk" json:"clerk"`
+	Worker    WorkerConfig    `yaml:"worker" json:"worker"`
+	Log       LogConfig       `yaml:"log" json:"log"`
+	Metrics   MetricsConfig   `yaml:"metrics" json:"metrics"`
+	Tracing   TracingConfig   `yaml:"tracing" json:"tracing"`
+	Storage   StorageConfig   `yaml:"storage" json:"storage"`
+	Export    ExportConfig    `yaml:"export" json:"export"`
+	Retention RetentionConfig `yaml:"retention" json:"retention"`
+}
+
+type ServerConfig struct {
+	Port        string `yaml:"port" json:"port" env:"SERVER_PORT" default:"8080"`
+	FrontendURL string `yaml:"frontend_url" json:"frontend_url" env:"FRONTEND_URL"`
+	GinMode     string `yaml:"gin_mode" json:"gin_mode" env:"GIN_MODE" default:"debug"`
+	// X-Forwarded-ForなどからクライアントのIPを取り出してよいプロキシ（IPまたはCIDRのカンマ区切り）
+	// 未設定の場合はどのプロキシも信頼せず、接続元のIPを使う
+	TrustedProxies []string `yaml:"trusted_proxies" json:"trusted_proxies" env:"SERVER_TRUSTED_PROXIES"`
+
+	ReadTimeout       time.Duration `yaml:"read_timeout" json:"read_timeout" env:"SERVER_READ_TIMEOUT" default:"15s"`
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout" json:"read_header_timeout" env:"SERVER_READ_HEADER_TIMEOUT" default:"5s"`
+	WriteTimeout      time.Duration `yaml:"write_timeout" json:"write_timeout" env:"SERVER_WRITE_TIMEOUT" default:"30s"`
+	IdleTimeout       time.Duration `yaml:"idle_timeout" json:"idle_timeout" env:"SERVER_IDLE_TIMEOUT" default:"60s"`
+
+	// SIGTERM受信後、readinessを落としてから新規接続の受付を止めるまでの待ち時間
+	// ロードバランサーから外れるのを待つために使う
+	ShutdownDelay time.Duration `yaml:"shutdown_delay" json:"shutdown_delay" env:"SERVER_SHUTDOWN_DELAY" default:"0s"`
+	// 処理中リクエストの完了を待つ上限
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" json:"shutdown_timeout" env:"SERVER_SHUTDOWN_TIMEOUT" default:"20s"`
+}
+
+type DatabaseConfig struct {
+	Host     string `yaml:"host" json:"host" env:"POSTGRES_HOST" default:"localhost"`
+	Port     string `yaml:"port" json:"port" env:"POSTGRES_PORT" default:"5432"`
+	User     string `yaml:"user" json:"user" env:"POSTGRES_USER"`
+	Password Secret `yaml:"password" json:"password" env:"POSTGRES_PASSWORD"`
+	Name     string `yaml:"name" json:"name" env:"POSTGRES_DB"`
+	SSLMode  string `yaml:"sslmode" json:"sslmode" env:"POSTGRES_SSLMODE" default:"disable"`
+	TimeZone string `yaml:"timezone" json:"timezone" env:"POSTGRES_TIMEZONE" default:"Asia/Tokyo"`
+
+	// コネクションプール
+	MaxOpenConns    int           `yaml:"max_open_conns" json:"max_open_conns" env:"POSTGRES_MAX_OPEN_CONNS" default:"25"`
+	MaxIdleConns    int           `yaml:"max_idle_conns" json:"max_idle_conns" env:"POSTGRES_MAX_IDLE_CONNS" default:"10"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime" json:"conn_max_lifetime" env:"POSTGRES_CONN_MAX_LIFETIME" default:"30m"`
+	ConnMaxIdleTime time.Duration `yaml:"conn_max_idle_time" json:"conn_max_idle_time" env:"POSTGRES_CONN_MAX_IDLE_TIME" default:"5m"`
+
+	// これより遅いクエリは警告としてログに出す
+	SlowQueryThreshold time.Duration `yaml:"slow_query_threshold" json:"slow_query_threshold" env:"DB_SLOW_QUERY_THRESHOLD" default:"200ms"`
+
+	// 読み取りレプリカ（"host" または "host:port" のカンマ区切り）
+	// ユーザー・パスワード・DB名はプライマリと共通
+	ReplicaHosts []string `yaml:"replica_hosts" json:"replica_hosts" env:"POSTGRES_REPLICA_HOSTS"`
+}
+
+type ClerkConfig struct {
+	SecretKey     Secret `yaml:"secret_key" json:"secret_key" env:"CLERK_SECRET_KEY"`
+	WebhookSecret Secret `yaml:"webhook_secret" json:"webhook_secret" env:"CLERK_WEBHOOK_SECRET"`
+	// 管理APIを利用できる組織のID（org_...）。この組織のorg:adminだけが管理者になる
+	// 未設定の場合、管理APIはすべて拒否される
+	AdminOrganizationID string `yaml:"admin_organization_id" json:"admin_organization_id" env:"CLERK_ADMIN_ORGANIZATION_ID"`
+}
+
+type LogConfig struct {
+	// debug, info, warn, error
+	Level string `yaml:"level" json:"level" env:"LOG_LEVEL" default:"info"`
+	// json または text
+	Format string `yaml:"format" json:"format" env:"LOG_FORMAT" default:"json"`
+}
+
+type MetricsConfig struct {
+	Enabled bool   `yaml:"enabled" json:"enabled" env:"METRICS_ENABLED" default:"false"`
+	Path    string `yaml:"path" json:"path" env:"METRICS_PATH" default:"/metrics"`
+	// workerはHTTPサーバーを持たないため、メトリクス専用のポートで公開する
+	WorkerPort string `yaml:"worker_port" json:"worker_port" env:"METRICS_WORKER_PORT" default:"9091"`
+}
+
+type TracingConfig struct {
+	Enabled bool `yaml:"enabled" json:"enabled" env:"TRACING_ENABLED" default:"false"`
+	// OTLP/HTTPの送信先（例: http://localhost:4318）
+	// 省略した場合はOTEL_EXPORTER_OTLP_ENDPOINTなどSDK標準の環境変数に従う
+	Endpoint    string `yaml:"endpoint" json:"endpoint" env:"TRACING_ENDPOINT"`
+	ServiceName string `yaml:"service_name" json:"service_name" env:"OTEL_SERVICE_NAME" default:"seat-management-backend"`
+	// 親スパンのないトレースを記録する割合（0〜1）
+	SampleRatio float64 `yaml:"sample_ratio" json:"sample_ratio" env:"TRACING_SAMPLE_RATIO" default:"1"`
+}
+
+type StorageConfig struct {
+	// local または s3（MinIOなどS3互換のストレージを含む）
+	Backend string `yaml:"backend" json:"backend" env:"STORAGE_BACKEND" default:"local"`
+	// 保存したファイルを配信するURLの先頭部分（末尾の/は不要）
+	// localの場合はサーバーの /uploads を指すこと
+	// s3の場合、公開読み取りを許可するのは avatars/ 以下だけにすること（exports/ には個人データが入る）
+	PublicBaseURL string `yaml:"public_base_url" json:"public_base_url" env:"STORAGE_PUBLIC_BASE_URL" default:"http://localhost:8080/uploads"`
+	// アップロードを受け付ける最大サイズ（バイト）
+	MaxUploadBytes int64 `yaml:"max_upload_bytes" json:"max_upload_bytes" env:"STORAGE_MAX_UPLOAD_BYTES" default:"5242880"`
+
+	LocalDir string `yaml:"local_dir" json:"local_dir" env:"STORAGE_LOCAL_DIR" default:"./data/uploads"`
+
+	S3Endpoint  string `yaml:"s3_endpoint" json:"s3_endpoint" env:"S3_ENDPOINT"`
+	S3Region    string `yaml:"s3_region" json:"s3_region" env:"S3_REGION" default:"ap-northeast-1"`
+	S3Bucket    string `yaml:"s3_bucket" json:"s3_bucket" env:"S3_BUCKET"`
+	S3AccessKey Secret `yaml:"s3_access_key" json:"s3_access_key" env:"S3_ACCESS_KEY"`
+	S3SecretKey Secret `yaml:"s3_secret_key" json:"s3_secret_key" env:"S3_SECRET_KEY"`
+	S3UseSSL    bool   `yaml:"s3_use_ssl" json:"s3_use_ssl" env:"S3_USE_SSL" default:"true"`
+}
+
+// ExportConfig は個人データのエクスポートの設定
+type ExportConfig struct {
+	// 合計のレコード数がこれ以下ならリクエスト内でアーカイブを作成し、超える場合は非同期ジョブにする
+	SyncMaxRecords int `yaml:"sync_max_records" json:"sync_max_records" env:"EXPORT_SYNC_MAX_RECORDS" default:"200"`
+	// 作成したアーカイブを保存しておく期間
+	Retention time.Duration `yaml:"retention" json:"retention" env:"EXPORT_RETENTION" default:"168h"`
+}
+
+// RetentionConfig は個人データの保存期間の設定
+type RetentionConfig struct {
+	// 削除したユーザーの個人情報（メールアドレス・名前など）を消去するまでの期間
+	// この期間内であれば管理者が削除を取り消せる
+	DeletedUsers time.Duration `yaml:"deleted_users" json:"deleted_users" env:"RETENTION_DELETED_USERS" default:"720h"`
+}
+
+type WorkerConfig struct {
+	ClerkSyncInterval time.Duration `yaml:"clerk_sync_interval" json:"clerk_sync_interval" env:"CLERK_SYNC_INTERVAL" default:"6h"`
+	// 削除済みユーザーの個人情報を消去する処理の実行間隔
+	UserAnonymizeInterval time.Duration `yaml:"user_anonymize_interval" json:"user_anonymize_interval" env:"USER_ANONYMIZE_INTERVAL" default:"1h"`
+	// 処理待ちのデータエクスポートを確認する間隔
+	DataExportInterval time.Duration `yaml:"data_export_interval" json:"data_export_interval" env:"DATA_EXPORT_INTERVAL" default:"30s"`
+	// 停止シグナル受信後、実行中のジョブの終了を待つ上限
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" json:"shutdown_timeout" env:"WORKER_SHUTDOWN_TIMEOUT" default:"30s"`
+}
+
+// Secret はログや表示で値を伏せる文字列
+type Secret string
+
+const redacted = "[REDACTED]"
+
+// Value は生の値を返す
+func (s Secret) Value() string {
+	return string(s)
+}
+
+func (s Secret) String() string {
+	if s == "" {
+		return ""
+	}
+	return redacted
+}
+
+func (s Secret) GoString() string {
+	return s.String()
+}
+
+func (s Secret) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+// String は秘密情報を伏せた設定内容を返す
+func (c *Config) String() string {
+	b, err := json.Marshal(c)
+	if err != nil {
+		return fmt.Sprintf("<config: %v>", err)
+	}
+	return string(b)
+}
+
+// Load は.env・YAMLファイル・環境変数から設定を読み込む
+// YAMLファイルのパスはCONFIG_FILEで指定し、省略した場合は読み込まない
+func Load() (*Config, error) {
+	if err := godotenv.Load(); err != nil {
+		slog.Debug("No .env file found")
+	}
+
+	cfg := &Config{}
+	if err := applyDefaults(reflect.ValueOf(cfg).Elem()); err != nil {
+		return nil, err
+	}
+
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		if err := loadYAML(path, cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := applyEnv(reflect.ValueOf(cfg).Elem()); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+func loadYAML(path string, cfg *Config) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("config file not found: %s", path)
+		}
+		return err
+	}
+	if err := yaml.Unmarshal(b, cfg); err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// Validate はすべてのコマンドで必要な設定を検証する
+func (c *Config) Validate() error {
+	var errs []error
+	if _, err := strconv.Atoi(c.Server.Port); err != nil {
+		errs = append(errs, fmt.Errorf("SERVER_PORT must be a number: %q", c.Server.Port))
+	}
+	if err := c.Database.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if c.Server.ShutdownTimeout <= 0 {
+		errs = append(errs, errors.New("SERVER_SHUTDOWN_TIMEOUT must be positive"))
+	}
+	if c.Worker.ClerkSyncInterval <= 0 {
+		errs = append(errs, errors.New("CLERK_SYNC_INTERVAL must be positive"))
+	}
+	if c.Worker.UserAnonymizeInterval <= 0 {
+		errs = append(errs, errors.New("USER_ANONYMIZE_INTERVAL must be positive"))
+	}
+	if c.Worker.DataExportInterval <= 0 {
+		errs = append(errs, errors.New("DATA_EXPORT_INTERVAL must be positive"))
+	}
+	if c.Worker.ShutdownTimeout <= 0 {
+		errs = append(errs, errors.New("WORKER_SHUTDOWN_TIMEOUT must be positive"))
+	}
+	if err := c.Storage.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if c.Export.SyncMaxRecords < 0 {
+		errs = append(errs, errors.New("EXPORT_SYNC_MAX_RECORDS must not be negative"))
+	}
+	if c.Export.Retention <= 0 {
+		errs = append(errs, errors.New("EXPORT_RETENTION must be positive"))
+	}
+	if c.Retention.DeletedUsers <= 0 {
+		errs = append(errs, errors.New("RETENTION_DELETED_USERS must be positive"))
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		errs = append(errs, errors.New("TRACING_SAMPLE_RATIO must be between 0 and 1"))
+	}
+	return errors.Join(errs...)
+}
+
+// Validate はデータベース接続に必要な設定を検証する
+func (c *DatabaseConfig) Validate() error {
+	var errs []error
+	if c.Host == "" {
+		errs = append(errs, errors.New("POSTGRES_HOST is not set"))
+	}
+	if c.User == "" {
+		errs = append(errs, errors.New("POSTGRES_USER is not set"))
+	}
+	if c.Name == "" {
+		errs = append(errs, errors.New("POSTGRES_DB is not set"))
+	}
+	if c.MaxOpenConns <= 0 {
+		errs = append(errs, errors.New("POSTGRES_MAX_OPEN_CONNS must be positive"))
+	}
+	if c.MaxIdleConns < 0 || c.MaxIdleConns > c.MaxOpenConns {
+		errs = append(errs, errors.New("POSTGRES_MAX_IDLE_CONNS must be between 0 and POSTGRES_MAX_OPEN_CONNS"))
+	}
+	return errors.Join(errs...)
+}
+
+// Validate はClerk APIの利用に必要な設定を検証する
+// requireWebhookがtrueの場合はWebhookの署名シークレットも必須とする
+func (c *ClerkConfig) Validate(requireWebhook bool) error {
+	var errs []error
+	if c.SecretKey == "" {
+		errs = append(errs, errors.New("CLERK_SECRET_KEY is not set"))
+	}
+	if requireWebhook && c.WebhookSecret == "" {
+		errs = append(errs, errors.New("CLERK_WEBHOOK_SECRET is not set"))
+	}
+	return errors.Join(errs...)
+}
+
+// Validate はストレージの設定を検証する
+func (c *StorageConfig) Validate() error {
+	var errs []error
+	switch c.Backend {
+	case "local":
+		if c.LocalDir == "" {
+			errs = append(errs, errors.New("STORAGE_LOCAL_DIR is not set"))
+		}
+	case "s3":
+		if c.S3Endpoint == "" {
+			errs = append(errs, errors.New("S3_ENDPOINT is not set"))
+		}
+		if c.S3Bucket == "" {
+			errs = append(errs, errors.New("S3_BUCKET is not set"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("STORAGE_BACKEND must be local or s3: %q", c.Backend))
+	}
+	if c.MaxUploadBytes <= 0 {
+		errs = append(errs, errors.New("STORAGE_MAX_UPLOAD_BYTES must be positive"))
+	}
+	return errors.Join(errs...)
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// applyDefaults はdefaultタグの値を設定する
+func applyDefaults(v reflect.Value) error {
+	return walk(v, func(field reflect.Value, tag reflect.StructTag) error {
+		def, ok := tag.Lookup("default")
+		if !ok {
+			return nil
+		}
+		return setValue(field, def)
+	})
+}
+
+// applyEnv はenvタグの環境変数が設定されていれば上書きする
+func applyEnv(v reflect.Value) error {
+	return walk(v, func(field reflect.Value, tag reflect.StructTag) error {
+		name := tag.Get("env")
+		if name == "" {
+			return nil
+		}
+		raw, ok := os.LookupEnv(name)
+		if !ok || raw == "" {
+			return nil
+		}
+		if err := setValue(field, raw); err != nil {
+			return fmt.Errorf("invalid %s: %w", name, err)
+		}
+		return nil
+	})
+}
+
+func walk(v reflect.Value, fn func(field reflect.Value, tag reflect.StructTag) error) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := v.Field(i)
+		if field.Kind() == reflect.Struct {
+			if err := walk(field, fn); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := fn(field, t.Field(i).Tag); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func setValue(field reflect.Value, raw string) error {
+	if field.Type() == durationType {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(d))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(raw)
+	case reflect.Slice:
+		if field.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported config field type: %s", field.Type())
+		}
+		var items []string
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		field.Set(reflect.ValueOf(items))
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return err
+		}
+		field.SetInt(n)
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return err
+		}
+		field.SetFloat(f)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	default:
+		return fmt.Errorf("unsupported config field type: %s", field.Type())
+	}
+	return nil
+}
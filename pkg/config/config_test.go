@@ -0,0 +1,172 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSecretRedaction(t *testing.T) {
+	s := Secret("hunter2")
+
+	if got := s.Value(); got != "hunter2" {
+		t.Errorf("Value() = %q, want raw value", got)
+	}
+	for name, got := range map[string]string{
+		"String": s.String(),
+		"%v":     fmt.Sprintf("%v", s),
+		"%s":     fmt.Sprintf("%s", s),
+		"%#v":    fmt.Sprintf("%#v", s),
+		"%+v":    fmt.Sprintf("%+v", struct{ Password Secret }{s}),
+		"Config": (&Config{Database: DatabaseConfig{Password: s}}).String(),
+	} {
+		if strings.Contains(got, "hunter2") {
+			t.Errorf("%s leaks the secret: %q", name, got)
+		}
+	}
+
+	if got := Secret("").String(); got != "" {
+		t.Errorf("empty Secret should print as empty, got %q", got)
+	}
+	if got := (&Config{Clerk: ClerkConfig{SecretKey: s}}).String(); !strings.Contains(got, `"secret_key":"[REDACTED]"`) {
+		t.Errorf("Config.String() should show the redacted marker, got %s", got)
+	}
+}
+
+// clearEnv はテスト中に読み込まれうる環境変数を未設定にする
+func clearEnv(t *testing.T) {
+	t.Helper()
+	t.Setenv("CONFIG_FILE", "")
+	for _, name := range []string{"SERVER_PORT", "POSTGRES_HOST", "POSTGRES_USER", "POSTGRES_DB", "POSTGRES_REPLICA_HOSTS", "SERVER_READ_TIMEOUT", "METRICS_ENABLED", "TRACING_SAMPLE_RATIO"} {
+		t.Setenv(name, "")
+		os.Unsetenv(name)
+	}
+}
+
+func TestLoadDefaults(t *testing.T) {
+	clearEnv(t)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+	if cfg.Server.Port != "8080" {
+		t.Errorf("Server.Port = %q, want default 8080", cfg.Server.Port)
+	}
+	if cfg.Server.ReadTimeout != 15*time.Second {
+		t.Errorf("Server.ReadTimeout = %v, want 15s", cfg.Server.ReadTimeout)
+	}
+	if cfg.Database.MaxOpenConns != 25 {
+		t.Errorf("Database.MaxOpenConns = %d, want 25", cfg.Database.MaxOpenConns)
+	}
+	if cfg.Tracing.SampleRatio != 1 {
+		t.Errorf("Tracing.SampleRatio = %v, want 1", cfg.Tracing.SampleRatio)
+	}
+	if cfg.Metrics.Enabled {
+		t.Error("Metrics.Enabled should default to false")
+	}
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	clearEnv(t)
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	yaml := "server:\n  port: \"9000\"\n  read_timeout: 3s\ndatabase:\n  host: yaml-host\n  user: yaml-user\n"
+	if err := os.WriteFile(path, []byte(yaml), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("CONFIG_FILE", path)
+	t.Setenv("POSTGRES_HOST", "env-host")
+	t.Setenv("POSTGRES_REPLICA_HOSTS", "replica-1, ,replica-2:5433")
+	t.Setenv("METRICS_ENABLED", "true")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+	if cfg.Server.Port != "9000" {
+		t.Errorf("Server.Port = %q, want value from YAML", cfg.Server.Port)
+	}
+	if cfg.Server.ReadTimeout != 3*time.Second {
+		t.Errorf("Server.ReadTimeout = %v, want value from YAML", cfg.Server.ReadTimeout)
+	}
+	if cfg.Database.Host != "env-host" {
+		t.Errorf("Database.Host = %q, environment should override YAML", cfg.Database.Host)
+	}
+	if cfg.Database.User != "yaml-user" {
+		t.Errorf("Database.User = %q, want value from YAML", cfg.Database.User)
+	}
+	if cfg.Database.Port != "5432" {
+		t.Errorf("Database.Port = %q, default should survive a YAML without the key", cfg.Database.Port)
+	}
+	if got := strings.Join(cfg.Database.ReplicaHosts, "|"); got != "replica-1|replica-2:5433" {
+		t.Errorf("Database.ReplicaHosts = %q, want blank items dropped", got)
+	}
+	if !cfg.Metrics.Enabled {
+		t.Error("Metrics.Enabled should be set from the environment")
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want string
+	}{
+		{name: "invalid duration", env: map[string]string{"SERVER_READ_TIMEOUT": "soon"}, want: "SERVER_READ_TIMEOUT"},
+		{name: "invalid float", env: map[string]string{"TRACING_SAMPLE_RATIO": "half"}, want: "TRACING_SAMPLE_RATIO"},
+		{name: "missing config file", env: map[string]string{"CONFIG_FILE": filepath.Join(t.TempDir(), "missing.yaml")}, want: "config file not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv(t)
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			_, err := Load()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("Load() error = %v, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	clearEnv(t)
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.Database.User = "app"
+	cfg.Database.Name = "seats"
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() on a complete config returned error: %v", err)
+	}
+
+	cfg.Server.Port = "http"
+	cfg.Database.User = ""
+	cfg.Tracing.SampleRatio = 2
+	err = cfg.Validate()
+	if err == nil {
+		t.Fatal("Validate() should fail")
+	}
+	for _, want := range []string{"SERVER_PORT", "POSTGRES_USER", "TRACING_SAMPLE_RATIO"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Validate() error should report %s, got: %v", want, err)
+		}
+	}
+}
+
+func TestClerkValidate(t *testing.T) {
+	c := ClerkConfig{SecretKey: "sk_test"}
+	if err := c.Validate(false); err != nil {
+		t.Errorf("Validate(false) returned error: %v", err)
+	}
+	if err := c.Validate(true); err == nil || !strings.Contains(err.Error(), "CLERK_WEBHOOK_SECRET") {
+		t.Errorf("Validate(true) error = %v, want missing webhook secret", err)
+	}
+}
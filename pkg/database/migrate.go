@@ -1,56 +1,316 @@
 package database
 
 import (
-	"log"
-
-	"seat-management-backend/internal/domain/entity"
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
 
 	"gorm.io/gorm"
 )
 
-// AutoMigrate
-func AutoMigrate(db *gorm.DB) error {
-	log.Println("Running database migrations...")
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// 複数レプリカが同時に起動してもマイグレーションが競合しないようにするためのロックキー
+const migrationLockKey int64 = 0x5ea7_0001
+
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// Migration はバージョン付きのマイグレーション
+type Migration struct {
+	Version int64
+	Name    string
+	UpSQL   string
+	DownSQL string
+
+	hasUp, hasDown bool
+}
+
+// MigrationStatus はマイグレーションの適用状況
+type MigrationStatus struct {
+	Version   int64
+	Name      string
+	AppliedAt *time.Time
+}
+
+// Migrator は埋め込みSQLファイルによるマイグレーションを実行する
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+}
+
+// NewMigrator は埋め込みのマイグレーションファイルを読み込んだMigratorを返す
+func NewMigrator(db *gorm.DB) (*Migrator, error) {
+	sub, err := fs.Sub(migrationFiles, "migrations")
+	if err != nil {
+		return nil, err
+	}
 
-	// ENUM型を作成（存在しない場合のみ）
-	if err := createEnums(db); err != nil {
-		return err
+	migrations, err := LoadMigrations(sub)
+	if err != nil {
+		return nil, err
 	}
 
-	// テーブルを作成
-	err := db.AutoMigrate(
-		&entity.User{},
-	)
+	return &Migrator{db: db, migrations: migrations}, nil
+}
 
+// LoadMigrations はファイルシステムからマイグレーションを読み込み、バージョン順に並べる
+func LoadMigrations(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	log.Println("Database migrations completed successfully")
-	return nil
+	byVersion := map[int64]*Migration{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		matches := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if matches == nil {
+			return nil, fmt.Errorf("invalid migration file name: %s", entry.Name())
+		}
+
+		version, err := strconv.ParseInt(matches[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version: %s", entry.Name())
+		}
+
+		body, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: matches[2]}
+			byVersion[version] = m
+		} else if m.Name != matches[2] {
+			return nil, fmt.Errorf("migration version %d has conflicting names: %s, %s", version, m.Name, matches[2])
+		}
+
+		if matches[3] == "up" {
+			m.UpSQL, m.hasUp = string(body), true
+		} else {
+			m.DownSQL, m.hasDown = string(body), true
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if !m.hasUp || !m.hasDown {
+			return nil, fmt.Errorf("migration %04d_%s must have both up and down files", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
 }
 
-// ENUM型を作成
-func createEnums(db *gorm.DB) error {
-	enums := []string{
-		`DO $$ BEGIN
-            CREATE TYPE privacy_setting_enum AS ENUM('public', 'friends', 'private');
-        EXCEPTION
-            WHEN duplicate_object THEN null;
-        END $$;`,
-		`DO $$ BEGIN
-            CREATE TYPE auth_provider_enum AS ENUM('email', 'google', 'unknown');
-        EXCEPTION
-            WHEN duplicate_object THEN null;
-        END $$;`,
+// Up は未適用のマイグレーションを古い順に適用する
+// stepsが0以下の場合はすべて適用する
+func (m *Migrator) Up(ctx context.Context, steps int) (int, error) {
+	applied := 0
+	err := m.withLock(ctx, func(conn *gorm.DB) error {
+		done, err := appliedVersions(conn)
+		if err != nil {
+			return err
+		}
+
+		for _, mig := range m.migrations {
+			if steps > 0 && applied >= steps {
+				break
+			}
+			if _, ok := done[mig.Version]; ok {
+				continue
+			}
+
+			slog.InfoContext(ctx, "applying migration", "version", mig.Version, "name", mig.Name)
+			err := conn.Transaction(func(tx *gorm.DB) error {
+				if err := tx.Exec(mig.UpSQL).Error; err != nil {
+					return err
+				}
+				return tx.Exec(
+					"INSERT INTO schema_migrations (version, name) VALUES (?, ?)",
+					mig.Version, mig.Name,
+				).Error
+			})
+			if err != nil {
+				return fmt.Errorf("migration %04d_%s failed: %w", mig.Version, mig.Name, err)
+			}
+			applied++
+		}
+		return nil
+	})
+
+	return applied, err
+}
+
+// Down は適用済みのマイグレーションを新しい順にsteps件ロールバックする
+func (m *Migrator) Down(ctx context.Context, steps int) (int, error) {
+	if steps <= 0 {
+		return 0, fmt.Errorf("invalid step count: %d (must be at least 1)", steps)
 	}
 
-	for _, enum := range enums {
-		if err := db.Exec(enum).Error; err != nil {
+	reverted := 0
+	err := m.withLock(ctx, func(conn *gorm.DB) error {
+		done, err := appliedVersions(conn)
+		if err != nil {
 			return err
 		}
+
+		for i := len(m.migrations) - 1; i >= 0 && reverted < steps; i-- {
+			mig := m.migrations[i]
+			if _, ok := done[mig.Version]; !ok {
+				continue
+			}
+
+			slog.InfoContext(ctx, "reverting migration", "version", mig.Version, "name", mig.Name)
+			err := conn.Transaction(func(tx *gorm.DB) error {
+				if err := tx.Exec(mig.DownSQL).Error; err != nil {
+					return err
+				}
+				return tx.Exec("DELETE FROM schema_migrations WHERE version = ?", mig.Version).Error
+			})
+			if err != nil {
+				return fmt.Errorf("rollback of %04d_%s failed: %w", mig.Version, mig.Name, err)
+			}
+			reverted++
+		}
+		return nil
+	})
+
+	return reverted, err
+}
+
+// Status はすべてのマイグレーションの適用状況を返す
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	conn := m.db.WithContext(ctx)
+	if err := ensureMigrationsTable(conn); err != nil {
+		return nil, err
+	}
+
+	done, err := appliedVersions(conn)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(m.migrations))
+	for _, mig := range m.migrations {
+		status := MigrationStatus{Version: mig.Version, Name: mig.Name}
+		if appliedAt, ok := done[mig.Version]; ok {
+			status.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// Pending は未適用のマイグレーション件数を返す
+// ヘルスチェックから呼ばれるため、schema_migrationsテーブルは作成しない
+func (m *Migrator) Pending(ctx context.Context) (int, error) {
+	conn := m.db.WithContext(ctx)
+
+	var exists bool
+	if err := conn.Raw("SELECT to_regclass('schema_migrations') IS NOT NULL").Scan(&exists).Error; err != nil {
+		return 0, err
+	}
+	if !exists {
+		return len(m.migrations), nil
+	}
+
+	done, err := appliedVersions(conn)
+	if err != nil {
+		return 0, err
+	}
+
+	pending := 0
+	for _, mig := range m.migrations {
+		if _, ok := done[mig.Version]; !ok {
+			pending++
+		}
+	}
+	return pending, nil
+}
+
+// withLock はアドバイザリロックを取得した単一コネクション上でfnを実行する
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *gorm.DB) error) error {
+	return m.db.WithContext(ctx).Connection(func(conn *gorm.DB) error {
+		if err := conn.Exec("SELECT pg_advisory_lock(?)", migrationLockKey).Error; err != nil {
+			return fmt.Errorf("failed to acquire migration lock: %w", err)
+		}
+		defer func() {
+			if err := conn.Exec("SELECT pg_advisory_unlock(?)", migrationLockKey).Error; err != nil {
+				slog.ErrorContext(ctx, "failed to release migration lock", "error", err)
+			}
+		}()
+
+		if err := ensureMigrationsTable(conn); err != nil {
+			return err
+		}
+		return fn(conn)
+	})
+}
+
+func ensureMigrationsTable(db *gorm.DB) error {
+	return db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    bigint PRIMARY KEY,
+		name       varchar(255) NOT NULL,
+		applied_at timestamp with time zone NOT NULL DEFAULT CURRENT_TIMESTAMP
+	)`).Error
+}
+
+func appliedVersions(db *gorm.DB) (map[int64]time.Time, error) {
+	var rows []struct {
+		Version   int64
+		AppliedAt time.Time
+	}
+	if err := db.Raw("SELECT version, applied_at FROM schema_migrations").Scan(&rows).Error; err != nil {
+		return nil, err
 	}
 
-	log.Println("ENUM types created successfully")
-	return nil
+	done := make(map[int64]time.Time, len(rows))
+	for _, row := range rows {
+		done[row.Version] = row.AppliedAt
+	}
+	return done, nil
+}
+
+// CreateMigration は次のバージョン番号で空のup/downファイルを作成する
+func CreateMigration(dir, name string) ([]string, error) {
+	if !regexp.MustCompile(`^[a-z0-9_]+$`).MatchString(name) {
+		return nil, fmt.Errorf("migration name must be snake_case: %s", name)
+	}
+
+	existing, err := LoadMigrations(os.DirFS(dir))
+	if err != nil {
+		return nil, err
+	}
+
+	var next int64 = 1
+	if len(existing) > 0 {
+		next = existing[len(existing)-1].Version + 1
+	}
+
+	var paths []string
+	for _, direction := range []string{"up", "down"} {
+		path := filepath.Join(dir, fmt.Sprintf("%04d_%s.%s.sql", next, name, direction))
+		body := fmt.Sprintf("-- %04d_%s (%s)\n", next, name, direction)
+		if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+			return nil, err
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
 }
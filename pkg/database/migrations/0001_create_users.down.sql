@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS users;
+DROP TYPE IF EXISTS auth_provider_enum;
+DROP TYPE IF EXISTS privacy_setting_enum;
@@ -0,0 +1,29 @@
+DO $$ BEGIN
+    CREATE TYPE privacy_setting_enum AS ENUM ('public', 'friends', 'private');
+EXCEPTION
+    WHEN duplicate_object THEN null;
+END $$;
+
+DO $$ BEGIN
+    CREATE TYPE auth_provider_enum AS ENUM ('email', 'google', 'unknown');
+EXCEPTION
+    WHEN duplicate_object THEN null;
+END $$;
+
+CREATE TABLE IF NOT EXISTS users (
+    id                      varchar(26) PRIMARY KEY,
+    clerk_user_id           varchar(255) NOT NULL,
+    email                   varchar(255) NOT NULL,
+    name                    varchar(100) NOT NULL,
+    avatar_url              varchar(500),
+    primary_auth_provider   auth_provider_enum DEFAULT 'unknown',
+    default_privacy_setting privacy_setting_enum DEFAULT 'private',
+    last_login_at           timestamp with time zone,
+    created_at              timestamp with time zone DEFAULT CURRENT_TIMESTAMP,
+    updated_at              timestamp with time zone DEFAULT CURRENT_TIMESTAMP,
+    deleted_at              timestamp with time zone
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_clerk_id ON users (clerk_user_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email ON users (email);
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users (deleted_at);
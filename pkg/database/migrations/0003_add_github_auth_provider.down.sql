@@ -0,0 +1,13 @@
+-- PostgreSQLはENUM値の削除をサポートしないため、型を作り直す
+UPDATE users SET primary_auth_provider = 'unknown' WHERE primary_auth_provider = 'github';
+
+ALTER TYPE auth_provider_enum RENAME TO auth_provider_enum_old;
+CREATE TYPE auth_provider_enum AS ENUM ('email', 'google', 'unknown');
+
+ALTER TABLE users ALTER COLUMN primary_auth_provider DROP DEFAULT;
+ALTER TABLE users
+    ALTER COLUMN primary_auth_provider TYPE auth_provider_enum
+    USING primary_auth_provider::text::auth_provider_enum;
+ALTER TABLE users ALTER COLUMN primary_auth_provider SET DEFAULT 'unknown';
+
+DROP TYPE auth_provider_enum_old;
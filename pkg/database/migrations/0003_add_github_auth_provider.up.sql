@@ -0,0 +1 @@
+ALTER TYPE auth_provider_enum ADD VALUE IF NOT EXISTS 'github';
@@ -0,0 +1,23 @@
+DO $$ BEGIN
+    CREATE TYPE data_export_status_enum AS ENUM ('pending', 'processing', 'completed', 'failed', 'expired');
+EXCEPTION
+    WHEN duplicate_object THEN null;
+END $$;
+
+CREATE TABLE IF NOT EXISTS data_exports (
+    id           varchar(26) PRIMARY KEY,
+    user_id      varchar(26) NOT NULL REFERENCES users (id),
+    status       data_export_status_enum NOT NULL DEFAULT 'pending',
+    object_key   varchar(255),
+    size_bytes   bigint,
+    error        text,
+    started_at   timestamp with time zone,
+    completed_at timestamp with time zone,
+    expires_at   timestamp with time zone,
+    created_at   timestamp with time zone DEFAULT CURRENT_TIMESTAMP,
+    updated_at   timestamp with time zone DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_data_exports_user_id ON data_exports (user_id);
+-- ワーカーが未処理のジョブを古い順に取り出すため
+CREATE INDEX IF NOT EXISTS idx_data_exports_pending ON data_exports (created_at) WHERE status IN ('pending', 'processing');
@@ -0,0 +1,8 @@
+-- 削除済みの行と重複するユーザーがいる場合は失敗する
+DROP INDEX IF EXISTS idx_users_email;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email ON users (email);
+
+DROP INDEX IF EXISTS idx_users_clerk_id;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_clerk_id ON users (clerk_user_id);
+
+ALTER TABLE users DROP COLUMN IF EXISTS anonymized_at;
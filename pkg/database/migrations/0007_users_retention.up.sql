@@ -0,0 +1,8 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS anonymized_at timestamp with time zone;
+
+-- 削除済みのユーザーと同じメールアドレス・Clerk IDで再登録できるよう、一意制約を未削除の行だけに限定する
+DROP INDEX IF EXISTS idx_users_clerk_id;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_clerk_id ON users (clerk_user_id) WHERE deleted_at IS NULL;
+
+DROP INDEX IF EXISTS idx_users_email;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email ON users (email) WHERE deleted_at IS NULL;
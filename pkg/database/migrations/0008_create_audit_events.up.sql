@@ -0,0 +1,53 @@
+CREATE TABLE IF NOT EXISTS audit_events (
+    id          varchar(26) PRIMARY KEY,
+    occurred_at timestamp with time zone NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    actor_type  varchar(20) NOT NULL,
+    actor_id    varchar(255),
+    actor_role  varchar(100),
+    action      varchar(100) NOT NULL,
+    target_type varchar(50) NOT NULL,
+    target_id   varchar(255),
+    ip_address  varchar(45),
+    session_id  varchar(255),
+    request_id  varchar(64),
+    changes     jsonb
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_events_occurred_at ON audit_events (occurred_at);
+CREATE INDEX IF NOT EXISTS idx_audit_events_actor_id ON audit_events (actor_id);
+CREATE INDEX IF NOT EXISTS idx_audit_events_target ON audit_events (target_type, target_id);
+CREATE INDEX IF NOT EXISTS idx_audit_events_action ON audit_events (action);
+
+-- 監査ログは追記のみとし、更新・削除を拒否する
+-- 例外として、個人情報を消去する直前の削除済みユーザーが操作した記録に限り、
+-- 操作者のIDをそのユーザーの墓標のID（deleted_<users.id>）に置き換え、
+-- IPアドレスとセッションIDを消すことだけを許可する（他の列は変更できない）
+CREATE OR REPLACE FUNCTION audit_events_append_only() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP = 'UPDATE'
+        AND OLD.actor_type = 'user'
+        AND NEW.ip_address IS NULL
+        AND NEW.session_id IS NULL
+        AND (NEW.id, NEW.occurred_at, NEW.actor_type, NEW.actor_role, NEW.action,
+             NEW.target_type, NEW.target_id, NEW.request_id, NEW.changes)
+            IS NOT DISTINCT FROM
+            (OLD.id, OLD.occurred_at, OLD.actor_type, OLD.actor_role, OLD.action,
+             OLD.target_type, OLD.target_id, OLD.request_id, OLD.changes)
+        AND EXISTS (
+            SELECT 1 FROM users
+            WHERE users.clerk_user_id = OLD.actor_id
+              AND users.deleted_at IS NOT NULL
+              AND users.anonymized_at IS NULL
+              AND NEW.actor_id = 'deleted_' || users.id
+        )
+    THEN
+        RETURN NEW;
+    END IF;
+    RAISE EXCEPTION 'audit_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_audit_events_append_only ON audit_events;
+CREATE TRIGGER trg_audit_events_append_only
+    BEFORE UPDATE OR DELETE ON audit_events
+    FOR EACH ROW EXECUTE FUNCTION audit_events_append_only();
@@ -1,35 +1,104 @@
 package database
 
 import (
+	"context"
 	"fmt"
-	"log"
-	"os"
+	"log/slog"
+	"net"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
+	"gorm.io/plugin/dbresolver"
+
+	"seat-management-backend/pkg/config"
+	"seat-management-backend/pkg/tracing"
 )
 
-func NewPostgresDB() (*gorm.DB, error) {
-	dsn := fmt.Sprintf(
-		"host=%s user=%s password=%s dbname=%s port=%s sslmode=%s TimeZone=%s",
-		os.Getenv("POSTGRES_HOST"),
-		os.Getenv("POSTGRES_USER"),
-		os.Getenv("POSTGRES_PASSWORD"),
-		os.Getenv("POSTGRES_DB"),
-		os.Getenv("POSTGRES_PORT"),
-		os.Getenv("POSTGRES_SSLMODE"),
-		os.Getenv("POSTGRES_TIMEZONE"),
-	)
+// replicaResolver は読み取りレプリカを明示的に使うためのリゾルバー名
+// テーブル名と衝突しない名前にしているため、自動振り分けの対象にはならない
+const replicaResolver = "read_replica"
 
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Info),
+func NewPostgresDB(cfg config.DatabaseConfig) (*gorm.DB, error) {
+	db, err := gorm.Open(postgres.Open(dsn(cfg, cfg.Host, cfg.Port)), &gorm.Config{
+		Logger: NewGormLogger(cfg.SlowQueryThreshold),
 	})
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
-	log.Println("Database connection established")
+	// トレーシングが無効な場合は何も記録しないTracerが使われる
+	if err := db.Use(tracing.NewGormPlugin()); err != nil {
+		return nil, fmt.Errorf("failed to register tracing plugin: %w", err)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+
+	if len(cfg.ReplicaHosts) > 0 {
+		if err := registerReplicas(db, cfg); err != nil {
+			return nil, fmt.Errorf("failed to configure read replicas: %w", err)
+		}
+		slog.Info("read replicas configured", "count", len(cfg.ReplicaHosts))
+	}
+
+	slog.Info("database connection established")
 	return db, nil
 }
+
+// ReadReplica は読み取りレプリカにクエリを振り分ける
+// 書き込み直後の読み取りなど、遅延が許されない用途では使わないこと
+// レプリカが未設定の場合はプライマリに接続する
+func ReadReplica(db *gorm.DB) *gorm.DB {
+	return db.Clauses(dbresolver.Use(replicaResolver))
+}
+
+// Ping はデータベースへの疎通を確認する
+func Ping(ctx context.Context, db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+func registerReplicas(db *gorm.DB, cfg config.DatabaseConfig) error {
+	replicas := make([]gorm.Dialector, 0, len(cfg.ReplicaHosts))
+	for _, hostPort := range cfg.ReplicaHosts {
+		host, port := hostPort, cfg.Port
+		if h, p, err := net.SplitHostPort(hostPort); err == nil {
+			host, port = h, p
+		}
+		replicas = append(replicas, postgres.Open(dsn(cfg, host, port)))
+	}
+
+	resolver := dbresolver.Register(dbresolver.Config{
+		Replicas: replicas,
+		Policy:   dbresolver.RandomPolicy{},
+	}, replicaResolver).
+		SetMaxOpenConns(cfg.MaxOpenConns).
+		SetMaxIdleConns(cfg.MaxIdleConns).
+		SetConnMaxLifetime(cfg.ConnMaxLifetime).
+		SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+
+	return db.Use(resolver)
+}
+
+func dsn(cfg config.DatabaseConfig, host, port string) string {
+	return fmt.Sprintf(
+		"host=%s user=%s password=%s dbname=%s port=%s sslmode=%s TimeZone=%s",
+		host,
+		cfg.User,
+		cfg.Password.Value(),
+		cfg.Name,
+		port,
+		cfg.SSLMode,
+		cfg.TimeZone,
+	)
+}
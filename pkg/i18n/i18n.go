@@ -0,0 +1,129 @@
+package i18n
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"golang.org/x/text/language"
+)
+
+// Locale は対応している言語
+type Locale string
+
+const (
+	Japanese Locale = "ja"
+	English  Locale = "en"
+
+	// Default はメッセージの言語が決まらない場合に使う言語
+	Default = Japanese
+)
+
+// supported は対応言語の一覧（先頭がデフォルト）
+var supported = []Locale{Japanese, English}
+
+//go:embed locales/*.json
+var localeFiles embed.FS
+
+// catalogue は言語ごとのメッセージ（キー: エラーコードまたはメッセージID）
+var catalogue = mustLoad()
+
+var matcher = language.NewMatcher([]language.Tag{language.Japanese, language.English})
+
+func mustLoad() map[Locale]map[string]string {
+	c := make(map[Locale]map[string]string, len(supported))
+	for _, l := range supported {
+		b, err := localeFiles.ReadFile("locales/" + string(l) + ".json")
+		if err != nil {
+			panic(fmt.Sprintf("i18n: missing bundle for %s: %v", l, err))
+		}
+		var messages map[string]string
+		if err := json.Unmarshal(b, &messages); err != nil {
+			panic(fmt.Sprintf("i18n: invalid bundle for %s: %v", l, err))
+		}
+		c[l] = messages
+	}
+	return c
+}
+
+// Supported は対応言語の一覧を返す
+func Supported() []Locale {
+	return append([]Locale(nil), supported...)
+}
+
+// ParseLocale は文字列を対応言語に変換する
+// "en-US" のような地域付きの指定は言語部分で判定する
+func ParseLocale(s string) (Locale, bool) {
+	base, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(s)), "-")
+	for _, l := range supported {
+		if string(l) == base {
+			return l, true
+		}
+	}
+	return "", false
+}
+
+// MatchAcceptLanguage はAccept-Languageヘッダーから最も適した対応言語を選ぶ
+// ヘッダーがない・解釈できない場合はDefaultを返す
+func MatchAcceptLanguage(header string) Locale {
+	if header == "" {
+		return Default
+	}
+	tags, _, err := language.ParseAcceptLanguage(header)
+	if err != nil || len(tags) == 0 {
+		return Default
+	}
+	_, index, confidence := matcher.Match(tags...)
+	if confidence == language.No {
+		return Default
+	}
+	return supported[index]
+}
+
+// T はキーに対応するメッセージを返す
+// 指定の言語にない場合はDefaultの言語、それもなければキーをそのまま返す
+func T(locale Locale, key string) string {
+	if msg, ok := catalogue[locale][key]; ok {
+		return msg
+	}
+	if msg, ok := catalogue[Default][key]; ok {
+		return msg
+	}
+	return key
+}
+
+// Render はキーに対応するメッセージをテンプレートとして展開する
+// メールや通知の件名・本文、検証エラーのメッセージなど値を埋め込む文言に使う
+// （プレーンテキストのためHTMLエスケープはしない）
+func Render(locale Locale, key string, data any) (string, error) {
+	tmpl, err := template.New(key).Option("missingkey=error").Parse(T(locale, key))
+	if err != nil {
+		return "", fmt.Errorf("i18n: invalid template %s: %w", key, err)
+	}
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("i18n: failed to render %s: %w", key, err)
+	}
+	return sb.String(), nil
+}
+
+type ctxKey struct{}
+
+// WithLocale は言語をコンテキストに設定する
+func WithLocale(ctx context.Context, locale Locale) context.Context {
+	return context.WithValue(ctx, ctxKey{}, locale)
+}
+
+// FromContext はコンテキストの言語を返す（未設定の場合はDefault）
+func FromContext(ctx context.Context) Locale {
+	if ctx == nil {
+		return Default
+	}
+	if l, ok := ctx.Value(ctxKey{}).(Locale); ok {
+		return l
+	}
+	return Default
+}
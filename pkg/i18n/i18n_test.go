@@ -0,0 +1,48 @@
+package i18n
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestCataloguesHaveTheSameKeys(t *testing.T) {
+	want := slices.Sorted(maps.Keys(catalogue[Default]))
+	for _, l := range Supported() {
+		if got := slices.Sorted(maps.Keys(catalogue[l])); !slices.Equal(got, want) {
+			t.Errorf("%s keys differ from %s:\n got %v\nwant %v", l, Default, got, want)
+		}
+	}
+}
+
+func TestRenderNotificationTemplates(t *testing.T) {
+	keys := []string{
+		"notification.user_suspended.subject",
+		"notification.user_suspended.body",
+		"notification.user_unsuspended.subject",
+		"notification.user_unsuspended.body",
+	}
+	data := struct{ Name string }{Name: "Taro"}
+	for _, l := range Supported() {
+		for _, key := range keys {
+			got, err := Render(l, key, data)
+			if err != nil {
+				t.Errorf("Render(%s, %s): %v", l, key, err)
+				continue
+			}
+			if got == key {
+				t.Errorf("Render(%s, %s) returned the key; the template is missing", l, key)
+			}
+			if strings.HasSuffix(key, ".body") && !strings.Contains(got, "Taro") {
+				t.Errorf("Render(%s, %s) = %q, want the name filled in", l, key, got)
+			}
+		}
+	}
+}
+
+func TestRenderMissingField(t *testing.T) {
+	if _, err := Render(English, "notification.user_suspended.body", map[string]string{}); err == nil {
+		t.Error("Render with a missing field should fail")
+	}
+}
@@ -0,0 +1,76 @@
+package imageproc
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+
+	// デコーダーの登録
+	_ "image/gif"
+	_ "image/png"
+
+	"github.com/disintegration/imaging"
+	"github.com/gabriel-vasile/mimetype"
+	_ "golang.org/x/image/webp"
+)
+
+// OutputContentType は変換後の画像の形式
+const OutputContentType = "image/jpeg"
+
+// 展開後のメモリ使用量を抑えるための上限（約4000万画素）
+const maxPixels = 40_000_000
+
+var (
+	// ErrUnsupportedType は対応していない形式のファイルの場合のエラー
+	ErrUnsupportedType = errors.New("imageproc: unsupported image type")
+	// ErrInvalidImage は画像として読み込めない、または大きすぎる場合のエラー
+	ErrInvalidImage = errors.New("imageproc: invalid image")
+)
+
+// allowedTypes は受け付ける画像の形式（拡張子やContent-Typeではなく内容から判定する）
+var allowedTypes = []string{"image/jpeg", "image/png", "image/gif", "image/webp"}
+
+// DetectType はファイルの内容から形式を判定する
+func DetectType(data []byte) (string, error) {
+	mt := mimetype.Detect(data)
+	for _, t := range allowedTypes {
+		if mt.Is(t) {
+			return t, nil
+		}
+	}
+	return mt.String(), ErrUnsupportedType
+}
+
+// Decode は画像を読み込む
+// JPEGのEXIFの向きを反映し、画素数が大きすぎる画像は展開する前に拒否する
+func Decode(data []byte) (image.Image, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImage, err)
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > maxPixels {
+		return nil, fmt.Errorf("%w: %dx%d", ErrInvalidImage, cfg.Width, cfg.Height)
+	}
+
+	img, err := imaging.Decode(bytes.NewReader(data), imaging.AutoOrientation(true))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImage, err)
+	}
+	return img, nil
+}
+
+// SquareJPEG は中央を正方形に切り抜いてsize×sizeに縮小し、JPEGにエンコードする
+// 透過部分は白で塗りつぶす。再エンコードによりEXIF（位置情報など）は取り除かれる
+func SquareJPEG(img image.Image, size int) ([]byte, error) {
+	resized := imaging.Fill(img, size, size, imaging.Center, imaging.Lanczos)
+	flattened := imaging.OverlayCenter(imaging.New(size, size, color.White), resized, 1.0)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, flattened, &jpeg.Options{Quality: 85}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
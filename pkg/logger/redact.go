@@ -0,0 +1,55 @@
+package logger
+
+import (
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+const redacted = "[REDACTED]"
+
+// 値をまるごと伏せるキー
+var sensitiveKeys = map[string]bool{
+	"token":         true,
+	"authorization": true,
+	"password":      true,
+	"secret":        true,
+	"signature":     true,
+	"payload":       true,
+}
+
+var (
+	emailPattern  = regexp.MustCompile(`([A-Za-z0-9._%+\-])[A-Za-z0-9._%+\-]*@([A-Za-z0-9.\-]+\.[A-Za-z]{2,})`)
+	bearerPattern = regexp.MustCompile(`(?i)bearer\s+[A-Za-z0-9\-._~+/]+=*`)
+	jwtPattern    = regexp.MustCompile(`eyJ[A-Za-z0-9_\-]+\.[A-Za-z0-9_\-]+\.[A-Za-z0-9_\-]+`)
+)
+
+// MaskEmail はメールアドレスのローカル部を先頭1文字以外伏せる
+func MaskEmail(email string) string {
+	return emailPattern.ReplaceAllString(email, "$1***@$2")
+}
+
+// Redact は文字列中のメールアドレスとトークンを伏せる
+func Redact(s string) string {
+	s = bearerPattern.ReplaceAllString(s, "Bearer "+redacted)
+	s = jwtPattern.ReplaceAllString(s, redacted)
+	return MaskEmail(s)
+}
+
+// redactAttr はslog.HandlerOptions.ReplaceAttrとして使う
+// メッセージを含むすべての文字列値とエラーに適用される
+func redactAttr(_ []string, a slog.Attr) slog.Attr {
+	if sensitiveKeys[strings.ToLower(a.Key)] {
+		return slog.String(a.Key, redacted)
+	}
+
+	switch a.Value.Kind() {
+	case slog.KindString:
+		return slog.String(a.Key, Redact(a.Value.String()))
+	case slog.KindAny:
+		if err, ok := a.Value.Any().(error); ok {
+			return slog.String(a.Key, Redact(err.Error()))
+		}
+	}
+	return a
+}
@@ -0,0 +1,71 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"errors"
+
+	ulidpkg "seat-management-backend/pkg/ulid"
+)
+
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+// ErrInvalidCursor はカーソルの形式が正しくない場合のエラー（ログ用）
+// クライアントにはリポジトリがentity.ErrInvalidCursorに変換して返す
+var ErrInvalidCursor = errors.New("pagination: invalid cursor")
+
+// Page はカーソルページネーションの結果
+type Page[T any] struct {
+	Items      []T    `json:"items"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// NormalizeLimit はlimitを有効な範囲に丸める
+func NormalizeLimit(limit int) int {
+	if limit <= 0 || limit > MaxLimit {
+		return DefaultLimit
+	}
+	return limit
+}
+
+// EncodeCursor はULIDを不透明なカーソル文字列に変換
+func EncodeCursor(id string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(id))
+}
+
+// DecodeCursor はカーソル文字列をULIDに戻す
+// 空文字列は先頭ページを表す
+func DecodeCursor(cursor string) (string, error) {
+	if cursor == "" {
+		return "", nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", ErrInvalidCursor
+	}
+
+	id := string(raw)
+	if !ulidpkg.IsValid(id) {
+		return "", ErrInvalidCursor
+	}
+	return id, nil
+}
+
+// NewPage はlimit+1件取得した結果からPageを組み立てる
+// 余分な1件があれば次ページが存在するとみなす
+// limitはNormalizeLimitと同じ規則で丸める
+func NewPage[T any](items []T, limit int, idOf func(T) string) *Page[T] {
+	limit = NormalizeLimit(limit)
+	page := &Page[T]{Items: items}
+	if len(items) > limit {
+		page.Items = items[:limit]
+		page.NextCursor = EncodeCursor(idOf(page.Items[limit-1]))
+	}
+	if page.Items == nil {
+		page.Items = []T{}
+	}
+	return page
+}
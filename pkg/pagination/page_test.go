@@ -0,0 +1,108 @@
+package pagination
+
+import (
+	"errors"
+	"testing"
+
+	ulidpkg "seat-management-backend/pkg/ulid"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	id := ulidpkg.Generate()
+
+	cursor := EncodeCursor(id)
+	if cursor == id {
+		t.Fatalf("cursor should not expose the raw ID: %q", cursor)
+	}
+
+	got, err := DecodeCursor(cursor)
+	if err != nil {
+		t.Fatalf("DecodeCursor(%q) returned error: %v", cursor, err)
+	}
+	if got != id {
+		t.Errorf("DecodeCursor(EncodeCursor(%q)) = %q", id, got)
+	}
+}
+
+func TestDecodeCursor(t *testing.T) {
+	tests := []struct {
+		name    string
+		cursor  string
+		want    string
+		wantErr error
+	}{
+		{name: "empty means first page", cursor: "", want: ""},
+		{name: "not base64", cursor: "!!!", wantErr: ErrInvalidCursor},
+		{name: "base64 of a non-ULID", cursor: EncodeCursor("not-a-ulid"), wantErr: ErrInvalidCursor},
+		{name: "padded base64", cursor: EncodeCursor(ulidpkg.Generate()) + "=", wantErr: ErrInvalidCursor},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeCursor(tt.cursor)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("DecodeCursor(%q) error = %v, want %v", tt.cursor, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("DecodeCursor(%q) = %q, want %q", tt.cursor, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeLimit(t *testing.T) {
+	tests := []struct {
+		limit int
+		want  int
+	}{
+		{limit: -1, want: DefaultLimit},
+		{limit: 0, want: DefaultLimit},
+		{limit: 1, want: 1},
+		{limit: MaxLimit, want: MaxLimit},
+		{limit: MaxLimit + 1, want: DefaultLimit},
+	}
+
+	for _, tt := range tests {
+		if got := NormalizeLimit(tt.limit); got != tt.want {
+			t.Errorf("NormalizeLimit(%d) = %d, want %d", tt.limit, got, tt.want)
+		}
+	}
+}
+
+func TestNewPage(t *testing.T) {
+	ids := make([]string, 4)
+	for i := range ids {
+		ids[i] = ulidpkg.Generate()
+	}
+	idOf := func(s string) string { return s }
+
+	tests := []struct {
+		name       string
+		items      []string
+		limit      int
+		wantItems  int
+		wantCursor string
+	}{
+		{name: "no items", items: nil, limit: 3, wantItems: 0},
+		{name: "fewer than limit", items: ids[:2], limit: 3, wantItems: 2},
+		{name: "exactly limit has no next page", items: ids[:3], limit: 3, wantItems: 3},
+		{name: "limit+1 has next page", items: ids[:4], limit: 3, wantItems: 3, wantCursor: EncodeCursor(ids[2])},
+		{name: "zero limit is normalised", items: ids[:4], limit: 0, wantItems: 4},
+		{name: "negative limit is normalised", items: ids[:4], limit: -1, wantItems: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page := NewPage(tt.items, tt.limit, idOf)
+			if page.Items == nil {
+				t.Fatal("Items should never be nil so that it encodes as []")
+			}
+			if len(page.Items) != tt.wantItems {
+				t.Errorf("len(Items) = %d, want %d", len(page.Items), tt.wantItems)
+			}
+			if page.NextCursor != tt.wantCursor {
+				t.Errorf("NextCursor = %q, want %q", page.NextCursor, tt.wantCursor)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// LocalRoutePrefix はローカルストアのファイルを配信するルート
+const LocalRoutePrefix = "/uploads"
+
+// LocalStore はローカルのファイルシステムに保存するBlobStore
+// 開発環境や単一インスタンスでの運用向け
+type LocalStore struct {
+	dir     string
+	baseURL string
+}
+
+// NewLocalStore はdir以下に保存するLocalStoreを作成する
+func NewLocalStore(dir, baseURL string) (*LocalStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create storage directory: %w", err)
+	}
+	return &LocalStore{dir: dir, baseURL: baseURL}, nil
+}
+
+// Dir は保存先のディレクトリを返す
+func (s *LocalStore) Dir() string {
+	return s.dir
+}
+
+// Put はファイルに保存する。PutOptionsの属性は保存しない（配信時はファイルの内容から判定する）
+func (s *LocalStore) Put(_ context.Context, key string, r io.Reader, _ int64, _ PutOptions) error {
+	p, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return err
+	}
+
+	// 書き込み途中のファイルが配信されないよう、一時ファイルに書いてから置き換える
+	tmp, err := os.CreateTemp(filepath.Dir(p), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), p)
+}
+
+func (s *LocalStore) Get(_ context.Context, key string) (io.ReadCloser, error) {
+	p, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(p)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return f, err
+}
+
+func (s *LocalStore) Delete(_ context.Context, key string) error {
+	p, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(p); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (s *LocalStore) URL(key string) string {
+	return publicURL(s.baseURL, key)
+}
+
+func (s *LocalStore) KeyFromURL(url string) (string, bool) {
+	return keyFromPublicURL(s.baseURL, url)
+}
+
+func (s *LocalStore) path(key string) (string, error) {
+	cleaned, err := cleanKey(key)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(s.dir, filepath.FromSlash(cleaned)), nil
+}
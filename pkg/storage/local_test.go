@@ -0,0 +1,144 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newTestLocalStore(t *testing.T) *LocalStore {
+	t.Helper()
+	store, err := NewLocalStore(filepath.Join(t.TempDir(), "uploads"), "http://localhost:8080/uploads/")
+	if err != nil {
+		t.Fatalf("NewLocalStore() returned error: %v", err)
+	}
+	return store
+}
+
+func TestLocalStorePutGetDelete(t *testing.T) {
+	store := newTestLocalStore(t)
+	ctx := context.Background()
+	key := "avatars/01HZX/256.jpg"
+
+	if err := store.Put(ctx, key, strings.NewReader("first"), 5, PutOptions{ContentType: "image/jpeg"}); err != nil {
+		t.Fatalf("Put() returned error: %v", err)
+	}
+	// 同じキーへの保存は置き換える
+	if err := store.Put(ctx, key, strings.NewReader("second"), 6, PutOptions{}); err != nil {
+		t.Fatalf("Put() overwrite returned error: %v", err)
+	}
+
+	rc, err := store.Get(ctx, key)
+	if err != nil {
+		t.Fatalf("Get() returned error: %v", err)
+	}
+	b, err := io.ReadAll(rc)
+	rc.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "second" {
+		t.Errorf("Get() = %q, want the last written content", b)
+	}
+
+	info, err := os.Stat(filepath.Join(store.Dir(), "avatars", "01HZX", "256.jpg"))
+	if err != nil {
+		t.Fatalf("file not written under Dir(): %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o644 {
+		t.Errorf("file mode = %o, want 644 so that the static handler can serve it", perm)
+	}
+	entries, err := os.ReadDir(filepath.Dir(filepath.Join(store.Dir(), key)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("temporary files were left behind: %v", entries)
+	}
+
+	if err := store.Delete(ctx, key); err != nil {
+		t.Fatalf("Delete() returned error: %v", err)
+	}
+	if _, err := store.Get(ctx, key); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get() after Delete() error = %v, want ErrNotFound", err)
+	}
+	if err := store.Delete(ctx, key); err != nil {
+		t.Errorf("Delete() of a missing key should succeed, got %v", err)
+	}
+}
+
+func TestLocalStorePutFailureKeepsPrevious(t *testing.T) {
+	store := newTestLocalStore(t)
+	ctx := context.Background()
+	key := "exports/01HZX/archive.zip"
+
+	if err := store.Put(ctx, key, strings.NewReader("complete"), 8, PutOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	failing := io.MultiReader(strings.NewReader("partial"), errReader{})
+	if err := store.Put(ctx, key, failing, 100, PutOptions{}); err == nil {
+		t.Fatal("Put() should return the reader's error")
+	}
+
+	rc, err := store.Get(ctx, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rc.Close()
+	if b, _ := io.ReadAll(rc); string(b) != "complete" {
+		t.Errorf("a failed Put() replaced the object with %q", b)
+	}
+}
+
+type errReader struct{}
+
+func (errReader) Read([]byte) (int, error) { return 0, errors.New("connection reset") }
+
+func TestLocalStoreRejectsInvalidKeys(t *testing.T) {
+	store := newTestLocalStore(t)
+	ctx := context.Background()
+
+	for _, key := range []string{"", "../escape.txt", "avatars/../../escape.txt", "avatars//double", "avatars/./dot"} {
+		if err := store.Put(ctx, key, strings.NewReader("x"), 1, PutOptions{}); err == nil {
+			t.Errorf("Put(%q) should be rejected", key)
+		}
+		if _, err := store.Get(ctx, key); err == nil || errors.Is(err, ErrNotFound) {
+			t.Errorf("Get(%q) error = %v, want an invalid key error", key, err)
+		}
+		if err := store.Delete(ctx, key); err == nil {
+			t.Errorf("Delete(%q) should be rejected", key)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(store.Dir()), "escape.txt")); !errors.Is(err, os.ErrNotExist) {
+		t.Error("a key escaped the storage directory")
+	}
+}
+
+func TestLocalStoreURL(t *testing.T) {
+	store := newTestLocalStore(t)
+	key := "avatars/01HZX/01J00/256.jpg"
+
+	url := store.URL(key)
+	if url != "http://localhost:8080/uploads/"+key {
+		t.Errorf("URL() = %q", url)
+	}
+
+	got, ok := store.KeyFromURL(url)
+	if !ok || got != key {
+		t.Errorf("KeyFromURL(URL(%q)) = %q, %v", key, got, ok)
+	}
+
+	for _, other := range []string{
+		"https://img.clerk.com/avatar.png",
+		"http://localhost:8080/uploads-other/" + key,
+		"http://localhost:8080/uploads/../secret",
+	} {
+		if key, ok := store.KeyFromURL(other); ok {
+			t.Errorf("KeyFromURL(%q) = %q, should not belong to the store", other, key)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+
+	"seat-management-backend/pkg/config"
+)
+
+// S3Store はS3互換のオブジェクトストレージに保存するBlobStore
+// エンドポイントを変えることでAWS S3とMinIOのどちらにも接続できる
+type S3Store struct {
+	client  *minio.Client
+	bucket  string
+	baseURL string
+}
+
+// NewS3Store はS3Storeを作成する
+// バケットが存在しない場合は作成する（MinIOを使った開発・テスト環境向け）
+func NewS3Store(ctx context.Context, cfg config.StorageConfig) (*S3Store, error) {
+	client, err := minio.New(cfg.S3Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(cfg.S3AccessKey.Value(), cfg.S3SecretKey.Value(), ""),
+		Secure: cfg.S3UseSSL,
+		Region: cfg.S3Region,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create S3 client: %w", err)
+	}
+
+	exists, err := client.BucketExists(ctx, cfg.S3Bucket)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check bucket %s: %w", cfg.S3Bucket, err)
+	}
+	if !exists {
+		if err := client.MakeBucket(ctx, cfg.S3Bucket, minio.MakeBucketOptions{Region: cfg.S3Region}); err != nil {
+			return nil, fmt.Errorf("failed to create bucket %s: %w", cfg.S3Bucket, err)
+		}
+	}
+
+	return &S3Store{client: client, bucket: cfg.S3Bucket, baseURL: cfg.PublicBaseURL}, nil
+}
+
+func (s *S3Store) Put(ctx context.Context, key string, r io.Reader, size int64, opts PutOptions) error {
+	key, err := cleanKey(key)
+	if err != nil {
+		return err
+	}
+	_, err = s.client.PutObject(ctx, s.bucket, key, r, size, minio.PutObjectOptions{
+		ContentType:  opts.ContentType,
+		CacheControl: opts.cacheControl(),
+	})
+	return err
+}
+
+func (s *S3Store) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	key, err := cleanKey(key)
+	if err != nil {
+		return nil, err
+	}
+	obj, err := s.client.GetObject(ctx, s.bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, err
+	}
+	// GetObjectは遅延して取得するため、存在確認のためにStatを呼ぶ
+	if _, err := obj.Stat(); err != nil {
+		obj.Close()
+		if minio.ToErrorResponse(err).Code == minio.NoSuchKey {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return obj, nil
+}
+
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	key, err := cleanKey(key)
+	if err != nil {
+		return err
+	}
+	return s.client.RemoveObject(ctx, s.bucket, key, minio.RemoveObjectOptions{})
+}
+
+func (s *S3Store) URL(key string) string {
+	return publicURL(s.baseURL, key)
+}
+
+func (s *S3Store) KeyFromURL(url string) (string, bool) {
+	return keyFromPublicURL(s.baseURL, url)
+}
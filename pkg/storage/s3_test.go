@@ -0,0 +1,127 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/minio/minio-go/v7"
+
+	"seat-management-backend/pkg/config"
+	"seat-management-backend/pkg/ulid"
+)
+
+// newTestS3Store はMinIOなどS3互換のストレージに接続するS3Storeを作成する
+// STORAGE_TEST_S3_ENDPOINTが未設定の場合はテストをスキップする
+//
+//	docker compose up -d minio
+//	STORAGE_TEST_S3_ENDPOINT=localhost:9000 go test ./pkg/storage
+func newTestS3Store(t *testing.T) *S3Store {
+	t.Helper()
+	endpoint := os.Getenv("STORAGE_TEST_S3_ENDPOINT")
+	if endpoint == "" {
+		t.Skip("STORAGE_TEST_S3_ENDPOINT is not set")
+	}
+
+	cfg := config.StorageConfig{
+		S3Endpoint:    endpoint,
+		S3Region:      "us-east-1",
+		S3Bucket:      "storage-test-" + strings.ToLower(ulid.Generate()),
+		S3AccessKey:   config.Secret(envOr("STORAGE_TEST_S3_ACCESS_KEY", "minioadmin")),
+		S3SecretKey:   config.Secret(envOr("STORAGE_TEST_S3_SECRET_KEY", "minioadmin")),
+		S3UseSSL:      os.Getenv("STORAGE_TEST_S3_USE_SSL") == "true",
+		PublicBaseURL: "http://" + endpoint + "/bucket",
+	}
+	store, err := NewS3Store(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("NewS3Store() returned error: %v", err)
+	}
+	t.Cleanup(func() {
+		ctx := context.Background()
+		for obj := range store.client.ListObjects(ctx, store.bucket, minio.ListObjectsOptions{Recursive: true}) {
+			_ = store.client.RemoveObject(ctx, store.bucket, obj.Key, minio.RemoveObjectOptions{})
+		}
+		_ = store.client.RemoveBucket(ctx, store.bucket)
+	})
+	return store
+}
+
+func envOr(name, def string) string {
+	if v := os.Getenv(name); v != "" {
+		return v
+	}
+	return def
+}
+
+func TestS3StorePutGetDelete(t *testing.T) {
+	store := newTestS3Store(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		key          string
+		opts         PutOptions
+		cacheControl string
+	}{
+		{key: "avatars/01HZX/256.jpg", opts: PutOptions{ContentType: "image/jpeg", CacheControl: CacheControlImmutable}, cacheControl: CacheControlImmutable},
+		{key: "exports/01HZX/01J00.zip", opts: PutOptions{ContentType: "application/zip", CacheControl: CacheControlPrivate}, cacheControl: CacheControlPrivate},
+		{key: "misc/default.bin", opts: PutOptions{ContentType: "application/octet-stream"}, cacheControl: CacheControlPrivate},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			body := "content of " + tt.key
+			if err := store.Put(ctx, tt.key, strings.NewReader(body), int64(len(body)), tt.opts); err != nil {
+				t.Fatalf("Put() returned error: %v", err)
+			}
+
+			info, err := store.client.StatObject(ctx, store.bucket, tt.key, minio.StatObjectOptions{})
+			if err != nil {
+				t.Fatalf("StatObject() returned error: %v", err)
+			}
+			if info.ContentType != tt.opts.ContentType {
+				t.Errorf("Content-Type = %q, want %q", info.ContentType, tt.opts.ContentType)
+			}
+			if got := info.Metadata.Get("Cache-Control"); got != tt.cacheControl {
+				t.Errorf("Cache-Control = %q, want %q", got, tt.cacheControl)
+			}
+
+			rc, err := store.Get(ctx, tt.key)
+			if err != nil {
+				t.Fatalf("Get() returned error: %v", err)
+			}
+			b, err := io.ReadAll(rc)
+			rc.Close()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(b) != body {
+				t.Errorf("Get() = %q, want %q", b, body)
+			}
+
+			if err := store.Delete(ctx, tt.key); err != nil {
+				t.Fatalf("Delete() returned error: %v", err)
+			}
+			if _, err := store.Get(ctx, tt.key); !errors.Is(err, ErrNotFound) {
+				t.Errorf("Get() after Delete() error = %v, want ErrNotFound", err)
+			}
+			if err := store.Delete(ctx, tt.key); err != nil {
+				t.Errorf("Delete() of a missing key should succeed, got %v", err)
+			}
+		})
+	}
+}
+
+func TestS3StoreRejectsInvalidKeys(t *testing.T) {
+	store := newTestS3Store(t)
+	ctx := context.Background()
+
+	if err := store.Put(ctx, "../escape", strings.NewReader("x"), 1, PutOptions{}); err == nil {
+		t.Error("Put() with a key outside the store should be rejected")
+	}
+	if _, err := store.Get(ctx, "avatars/../../escape"); err == nil {
+		t.Error("Get() with a key outside the store should be rejected")
+	}
+}
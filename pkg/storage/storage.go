@@ -0,0 +1,88 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"seat-management-backend/pkg/config"
+)
+
+// ErrNotFound は指定したキーのオブジェクトが存在しない場合のエラー
+var ErrNotFound = errors.New("storage: object not found")
+
+// オブジェクトに設定するCache-Control
+const (
+	// CacheControlImmutable は内容が変わらない公開オブジェクト（アップロードごとにキーを変えるアバター画像など）
+	CacheControlImmutable = "public, max-age=31536000, immutable"
+	// CacheControlPrivate は個人データを含むオブジェクト（共有キャッシュやブラウザに残さない）
+	CacheControlPrivate = "private, no-store"
+)
+
+// PutOptions はオブジェクトの保存時に設定する属性
+type PutOptions struct {
+	ContentType string
+	// 省略した場合はCacheControlPrivateになる
+	CacheControl string
+}
+
+func (o PutOptions) cacheControl() string {
+	if o.CacheControl == "" {
+		return CacheControlPrivate
+	}
+	return o.CacheControl
+}
+
+// BlobStore は画像などのファイルを保存するストレージ
+// キーは "avatars/<user_id>/..." のような/区切りの相対パスで指定する
+type BlobStore interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, opts PutOptions) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+	// URL はオブジェクトを配信するURLを返す
+	URL(key string) string
+	// KeyFromURL はこのストアのURLからキーを取り出す（他のURLの場合はfalse）
+	KeyFromURL(url string) (string, bool)
+}
+
+// New は設定に応じたBlobStoreを作成する
+func New(ctx context.Context, cfg config.StorageConfig) (BlobStore, error) {
+	switch cfg.Backend {
+	case "local":
+		return NewLocalStore(cfg.LocalDir, cfg.PublicBaseURL)
+	case "s3":
+		return NewS3Store(ctx, cfg)
+	default:
+		return nil, fmt.Errorf("unknown storage backend: %s", cfg.Backend)
+	}
+}
+
+// cleanKey はキーを正規化し、ストアの外を指すキーを拒否する
+func cleanKey(key string) (string, error) {
+	cleaned := path.Clean("/" + key)[1:]
+	if cleaned == "" || cleaned != strings.TrimPrefix(key, "/") {
+		return "", fmt.Errorf("storage: invalid key %q", key)
+	}
+	return cleaned, nil
+}
+
+// publicURL はベースURLとキーからURLを組み立てる
+func publicURL(baseURL, key string) string {
+	return strings.TrimRight(baseURL, "/") + "/" + key
+}
+
+// keyFromPublicURL はベースURLで始まるURLからキーを取り出す
+func keyFromPublicURL(baseURL, url string) (string, bool) {
+	prefix := strings.TrimRight(baseURL, "/") + "/"
+	if !strings.HasPrefix(url, prefix) {
+		return "", false
+	}
+	key, err := cleanKey(strings.TrimPrefix(url, prefix))
+	if err != nil {
+		return "", false
+	}
+	return key, true
+}
@@ -0,0 +1,212 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"seat-management-backend/pkg/config"
+)
+
+const (
+	incomingTraceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	incomingSpanID  = "00f067aa0ba902b7"
+)
+
+// setupTest はインメモリのエクスポーターでトレーシングを設定し、記録されたスパンを返す関数を返す
+func setupTest(t *testing.T) func() tracetest.SpanStubs {
+	t.Helper()
+	exporter := tracetest.NewInMemoryExporter()
+	shutdown, err := Setup(context.Background(), config.TracingConfig{
+		Enabled:     true,
+		ServiceName: "tracing-test",
+		SampleRatio: 1,
+	}, exporter)
+	if err != nil {
+		t.Fatalf("Setup() returned error: %v", err)
+	}
+	t.Cleanup(func() { _ = shutdown(context.Background()) })
+
+	return func() tracetest.SpanStubs {
+		t.Helper()
+		// Setupはバッチで送信するため、取得前に送信させる
+		tp, ok := otel.GetTracerProvider().(*sdktrace.TracerProvider)
+		if !ok {
+			t.Fatalf("unexpected tracer provider %T", otel.GetTracerProvider())
+		}
+		if err := tp.ForceFlush(context.Background()); err != nil {
+			t.Fatalf("ForceFlush() returned error: %v", err)
+		}
+		return exporter.GetSpans()
+	}
+}
+
+// newDryRunDB はDBに接続せずにSQLを組み立てるだけのgorm.DBを作成する
+func newDryRunDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=127.0.0.1 user=test dbname=test sslmode=disable"}), &gorm.Config{
+		DryRun:                 true,
+		DisableAutomaticPing:   true,
+		SkipDefaultTransaction: true,
+	})
+	if err != nil {
+		t.Fatalf("gorm.Open() returned error: %v", err)
+	}
+	if err := db.Use(NewGormPlugin()); err != nil {
+		t.Fatalf("db.Use() returned error: %v", err)
+	}
+	return db
+}
+
+type testUser struct {
+	ID    string
+	Email string
+}
+
+func newRouter(handler gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Middleware())
+	r.GET("/users/:id", handler)
+	return r
+}
+
+func findSpan(t *testing.T, spans tracetest.SpanStubs, name string) tracetest.SpanStub {
+	t.Helper()
+	for _, s := range spans {
+		if s.Name == name {
+			return s
+		}
+	}
+	t.Fatalf("span %q not found in %d spans", name, len(spans))
+	return tracetest.SpanStub{}
+}
+
+func hasAttribute(s tracetest.SpanStub, kv attribute.KeyValue) bool {
+	for _, attr := range s.Attributes {
+		if attr == kv {
+			return true
+		}
+	}
+	return false
+}
+
+func TestMiddlewareCreatesServerSpan(t *testing.T) {
+	spans := setupTest(t)
+	r := newRouter(func(c *gin.Context) { c.Status(http.StatusInternalServerError) })
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/01HZX", nil))
+
+	span := findSpan(t, spans(), "GET /users/:id")
+	if span.SpanKind != trace.SpanKindServer {
+		t.Errorf("SpanKind = %v, want server", span.SpanKind)
+	}
+	if span.Parent.IsValid() {
+		t.Errorf("span without traceparent should be a root span, got parent %v", span.Parent.SpanID())
+	}
+	for _, kv := range []attribute.KeyValue{
+		semconv.HTTPRoute("/users/:id"),
+		semconv.URLPath("/users/01HZX"),
+		semconv.HTTPResponseStatusCode(http.StatusInternalServerError),
+	} {
+		if !hasAttribute(span, kv) {
+			t.Errorf("missing attribute %s=%s", kv.Key, kv.Value.Emit())
+		}
+	}
+	if span.Status.Code.String() != "Error" {
+		t.Errorf("Status = %v, want Error for a 5xx response", span.Status.Code)
+	}
+}
+
+func TestMiddlewarePropagatesTraceparent(t *testing.T) {
+	spans := setupTest(t)
+	var handlerSpan trace.SpanContext
+	r := newRouter(func(c *gin.Context) {
+		handlerSpan = trace.SpanContextFromContext(c.Request.Context())
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/users/01HZX", nil)
+	req.Header.Set("traceparent", "00-"+incomingTraceID+"-"+incomingSpanID+"-01")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	span := findSpan(t, spans(), "GET /users/:id")
+	if got := span.SpanContext.TraceID().String(); got != incomingTraceID {
+		t.Errorf("TraceID = %s, want the incoming trace %s", got, incomingTraceID)
+	}
+	if got := span.Parent.SpanID().String(); got != incomingSpanID {
+		t.Errorf("parent SpanID = %s, want the incoming span %s", got, incomingSpanID)
+	}
+	if !span.Parent.IsRemote() {
+		t.Error("parent should be marked as remote")
+	}
+	if handlerSpan.SpanID() != span.SpanContext.SpanID() {
+		t.Error("handlers should see the server span in the request context")
+	}
+}
+
+func TestGormPluginCreatesChildSpans(t *testing.T) {
+	spans := setupTest(t)
+	db := newDryRunDB(t)
+	r := newRouter(func(c *gin.Context) {
+		var user testUser
+		db.WithContext(c.Request.Context()).Where("email = ?", "secret@example.com").Find(&user)
+		c.Status(http.StatusOK)
+	})
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/01HZX", nil))
+
+	recorded := spans()
+	server := findSpan(t, recorded, "GET /users/:id")
+	query := findSpan(t, recorded, "gorm.query")
+	if query.SpanKind != trace.SpanKindClient {
+		t.Errorf("SpanKind = %v, want client", query.SpanKind)
+	}
+	if query.Parent.SpanID() != server.SpanContext.SpanID() {
+		t.Errorf("gorm span parent = %s, want the server span %s", query.Parent.SpanID(), server.SpanContext.SpanID())
+	}
+	if query.SpanContext.TraceID() != server.SpanContext.TraceID() {
+		t.Error("gorm span should belong to the request trace")
+	}
+	if !hasAttribute(query, semconv.DBCollectionName("test_users")) {
+		t.Error("missing db.collection.name attribute")
+	}
+	// バインド値（メールアドレス）はスパンに残さない
+	if want := `SELECT * FROM "test_users" WHERE email = $1`; !hasAttribute(query, semconv.DBQueryText(want)) {
+		t.Errorf("db.query.text should be %q with placeholders only, got %v", want, query.Attributes)
+	}
+}
+
+func TestSetupDisabledStillPropagates(t *testing.T) {
+	shutdown, err := Setup(context.Background(), config.TracingConfig{Enabled: false}, nil)
+	if err != nil {
+		t.Fatalf("Setup() returned error: %v", err)
+	}
+	t.Cleanup(func() { _ = shutdown(context.Background()) })
+	otel.SetTracerProvider(noop.NewTracerProvider())
+
+	var got trace.SpanContext
+	r := newRouter(func(c *gin.Context) {
+		got = trace.SpanContextFromContext(c.Request.Context())
+	})
+	req := httptest.NewRequest(http.MethodGet, "/users/01HZX", nil)
+	req.Header.Set("traceparent", "00-"+incomingTraceID+"-"+incomingSpanID+"-01")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	if got.TraceID().String() != incomingTraceID {
+		t.Errorf("TraceID = %s, want the incoming trace to be kept when tracing is disabled", got.TraceID())
+	}
+}
@@ -11,3 +11,9 @@ func Generate() string {
 	entropy := ulid.Monotonic(rand.Reader, 0)
 	return ulid.MustNew(ulid.Timestamp(time.Now()), entropy).String()
 }
+
+// IsValid は文字列がULIDとして正しいかチェック
+func IsValid(s string) bool {
+	_, err := ulid.ParseStrict(s)
+	return err == nil
+}
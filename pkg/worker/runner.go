@@ -0,0 +1,79 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"seat-management-backend/pkg/audit"
+	"seat-management-backend/pkg/tracing"
+)
+
+// Job は一定間隔で実行されるバックグラウンドジョブ
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+// Runner は登録されたジョブをそれぞれの間隔で実行する
+type Runner struct {
+	jobs []Job
+}
+
+func NewRunner() *Runner {
+	return &Runner{}
+}
+
+// Register はジョブを登録する
+func (r *Runner) Register(job Job) {
+	r.jobs = append(r.jobs, job)
+}
+
+// Run はctxがキャンセルされるまでジョブを実行し、すべてのジョブの終了を待つ
+// 各ジョブは起動直後に1回実行され、以降はIntervalごとに実行される
+func (r *Runner) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, job := range r.jobs {
+		wg.Add(1)
+		go func(job Job) {
+			defer wg.Done()
+			r.loop(ctx, job)
+		}(job)
+	}
+	wg.Wait()
+}
+
+func (r *Runner) loop(ctx context.Context, job Job) {
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	for {
+		r.runOnce(ctx, job)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *Runner) runOnce(ctx context.Context, job Job) {
+	// 実行ごとに新しいトレースを開始する
+	ctx, span := tracing.Start(ctx, "worker."+job.Name, trace.WithNewRoot())
+	// ジョブによる変更は監査ログにジョブ名で記録する
+	ctx = audit.WithActor(ctx, audit.Actor{Type: audit.ActorSystem, ID: job.Name})
+	var err error
+	defer func() { tracing.End(span, err) }()
+
+	start := time.Now()
+	if err = job.Run(ctx); err != nil {
+		slog.ErrorContext(ctx, "worker: job failed", "job", job.Name, "error", err)
+		return
+	}
+	slog.InfoContext(ctx, "worker: job completed", "job", job.Name, "duration_ms", time.Since(start).Milliseconds())
+}